@@ -0,0 +1,233 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chacha implements a random number generator based on the ChaCha
+// stream cipher of D. J. Bernstein, https://cr.yp.to/chacha.html.
+//
+// The output of a Source is the ChaCha keystream for its key, with a zero
+// nonce and a 64-bit block counter starting at zero. Unlike the other
+// generators in this module, it is unpredictable to anyone who does not know
+// the key, provided the key is chosen at random.
+package chacha
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"github.com/greatroar/randstat/splitmix64"
+)
+
+const (
+	blockSize = 64
+	bufBlocks = 4 // Number of blocks generated at a time.
+)
+
+// A Source is a ChaCha random number generator.
+//
+// A Source must be initialized by New or Seed before use. The zero Source
+// panics when used, rather than producing a predictable stream.
+type Source struct {
+	key    [8]uint32
+	ctr    uint64 // Counter of the next block to generate.
+	rounds int
+
+	buf  [bufBlocks * blockSize]byte
+	left int // Number of unused bytes at the end of buf.
+}
+
+// New returns a Source that produces the keystream of ChaCha with the given
+// key and number of rounds, which must be 8, 12 or 20.
+//
+// ChaCha8 is the fastest, ChaCha20 has the widest security margin.
+func New(key [32]byte, rounds int) *Source {
+	switch rounds {
+	case 8, 12, 20:
+	default:
+		panic("chacha: rounds must be 8, 12 or 20")
+	}
+
+	s := &Source{rounds: rounds}
+	s.setKey(key[:])
+	return s
+}
+
+func (s *Source) setKey(key []byte) {
+	for i := range s.key {
+		s.key[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	s.ctr = 0
+	s.left = 0
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Read fills p with the next len(p) bytes of the keystream.
+// It always returns len(p), nil.
+func (s *Source) Read(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if s.left == 0 {
+			s.refill()
+		}
+		m := copy(p, s.buf[len(s.buf)-s.left:])
+		s.left -= m
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state, positioned at the start of its keystream.
+//
+// It uses a SplitMix64 generator to turn seed into a key, so a Source
+// seeded this way can produce only 2^64 distinct streams and is only
+// as unpredictable as its seed. Use New with a random key when predictability
+// matters. If s was not initialized by New, it uses ChaCha20.
+func (s *Source) Seed(seed int64) {
+	if s.rounds == 0 {
+		s.rounds = 20
+	}
+
+	var key [32]byte
+	sm := splitmix64.Source(seed)
+	for i := 0; i < len(key); i += 8 {
+		binary.LittleEndian.PutUint64(key[i:], sm.Uint64())
+	}
+	s.setKey(key[:])
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+// It consumes the next eight bytes of the keystream,
+// in little-endian order.
+func (s *Source) Uint64() uint64 {
+	if s.left >= 8 {
+		x := binary.LittleEndian.Uint64(s.buf[len(s.buf)-s.left:])
+		s.left -= 8
+		return x
+	}
+
+	var b [8]byte
+	s.Read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+func (s *Source) refill() {
+	if s.rounds == 0 {
+		panic("chacha: Source not initialized")
+	}
+	for i := 0; i < bufBlocks; i++ {
+		block(s.buf[i*blockSize:(i+1)*blockSize], &s.key, s.ctr, s.rounds)
+		s.ctr++
+	}
+	s.left = len(s.buf)
+}
+
+// block computes the keystream block for counter ctr into out.
+func block(out []byte, key *[8]uint32, ctr uint64, rounds int) {
+	x := [16]uint32{
+		0x61707865, 0x3320646e, 0x79622d32, 0x6b206574,
+		key[0], key[1], key[2], key[3],
+		key[4], key[5], key[6], key[7],
+		uint32(ctr), uint32(ctr >> 32), 0, 0,
+	}
+	in := x
+
+	for i := 0; i < rounds; i += 2 {
+		// Column round.
+		quarter(&x, 0, 4, 8, 12)
+		quarter(&x, 1, 5, 9, 13)
+		quarter(&x, 2, 6, 10, 14)
+		quarter(&x, 3, 7, 11, 15)
+		// Diagonal round.
+		quarter(&x, 0, 5, 10, 15)
+		quarter(&x, 1, 6, 11, 12)
+		quarter(&x, 2, 7, 8, 13)
+		quarter(&x, 3, 4, 9, 14)
+	}
+
+	for i := range x {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i]+in[i])
+	}
+}
+
+func quarter(x *[16]uint32, a, b, c, d int) {
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+}
+
+const (
+	header      = "chacha\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"
+	marshalSize = len(header) + 8 + 32 + 8 + 8
+)
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format starts with a 16-byte header, followed by the number of rounds,
+// the key, the block counter and the byte offset into that block.
+// The integers are 64-bit and little-endian.
+//
+// The returned error is always nil.
+func (s *Source) MarshalBinary() (data []byte, err error) {
+	// Position of the next unused keystream byte.
+	i := len(s.buf) - s.left
+	ctr := s.ctr - bufBlocks + uint64(i/blockSize)
+	off := i % blockSize
+
+	data = make([]byte, marshalSize)
+	copy(data, header)
+	p := data[len(header):]
+
+	binary.LittleEndian.PutUint64(p, uint64(s.rounds))
+	for i, k := range s.key {
+		binary.LittleEndian.PutUint32(p[8+4*i:], k)
+	}
+	binary.LittleEndian.PutUint64(p[40:], ctr)
+	binary.LittleEndian.PutUint64(p[48:], uint64(off))
+	return
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *Source) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) != marshalSize:
+		return errors.New("unmarshal chacha: incorrect data length")
+	case string(data[:len(header)]) != header:
+		return errors.New("unmarshal chacha: incorrect header")
+	}
+
+	p := data[len(header):]
+	rounds := binary.LittleEndian.Uint64(p)
+	off := binary.LittleEndian.Uint64(p[48:])
+	switch {
+	case rounds != 8 && rounds != 12 && rounds != 20:
+		return errors.New("unmarshal chacha: invalid number of rounds")
+	case off >= blockSize:
+		return errors.New("unmarshal chacha: invalid offset")
+	}
+
+	s.rounds = int(rounds)
+	s.setKey(p[8:40])
+	s.ctr = binary.LittleEndian.Uint64(p[40:])
+	if off > 0 {
+		s.refill()
+		s.left = len(s.buf) - int(off)
+	}
+	return nil
+}
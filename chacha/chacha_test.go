@@ -0,0 +1,159 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chacha_test
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/chacha"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*chacha.Source)(nil)
+	_ encoding.BinaryUnmarshaler = (*chacha.Source)(nil)
+	_ io.Reader                  = (*chacha.Source)(nil)
+	_ rand.Source64              = (*chacha.Source)(nil)
+)
+
+func TestKeystream(t *testing.T) {
+	t.Parallel()
+
+	// First block of keystream for the all-zero key and nonce, from
+	// draft-strombergson-chacha-test-vectors-01 (ChaCha8, ChaCha12)
+	// and RFC 7539 (ChaCha20).
+	for _, c := range []struct {
+		rounds int
+		hex    string
+	}{
+		{8, "3e00ef2f895f40d67f5bb8e81f09a5a12c840ec3ce9a7f3b181be188ef711a1e" +
+			"984ce172b9216f419f445367456d5619314a42a3da86b001387bfdb80e0cfe42"},
+		{12, "9bf49a6a0755f953811fce125f2683d50429c3bb49e074147e0089a52eae155f" +
+			"0564f879d27ae3c02ce82834acfa8c793a629f2ca0de6919610be82f411326be"},
+		{20, "76b8e0ada0f13d90405d6ae55386bd28bdd219b8a08ded1aa836efcc8b770dc7" +
+			"da41597c5157488d7724e03fb8d84a376a43b8f41518a11cc387b669b2ee6586"},
+	} {
+		s := chacha.New([32]byte{}, c.rounds)
+		got := make([]byte, 64)
+		n, err := s.Read(got)
+		assert.Equal(t, 64, n)
+		assert.Nil(t, err)
+		assert.Equal(t, c.hex, hex.EncodeToString(got), "ChaCha%d", c.rounds)
+	}
+
+	assert.Panics(t, func() { chacha.New([32]byte{}, 10) })
+}
+
+// Uint64 and Read consume the same keystream.
+func TestUint64Read(t *testing.T) {
+	t.Parallel()
+
+	key := [32]byte{1, 2, 3}
+	stream := make([]byte, 4000)
+	chacha.New(key, 8).Read(stream)
+
+	s := chacha.New(key, 8)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < len(stream)-100; {
+		if r.Intn(2) == 0 {
+			assert.Equal(t, binary.LittleEndian.Uint64(stream[i:]), s.Uint64())
+			i += 8
+			continue
+		}
+		b := make([]byte, r.Intn(100))
+		s.Read(b)
+		assert.Equal(t, stream[i:i+len(b)], b)
+		i += len(b)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	s := chacha.New([32]byte{9}, 12)
+	b := make([]byte, 13)
+
+	for i := 0; i < 100; i++ {
+		s.Read(b)
+
+		marshaled, err := s.MarshalBinary()
+		assert.Nil(t, err)
+
+		expect := s.Uint64()
+
+		var u chacha.Source
+		err = u.UnmarshalBinary(marshaled)
+		assert.Nil(t, err)
+
+		actual := u.Uint64()
+		assert.Equal(t, expect, actual)
+	}
+
+	var u chacha.Source
+	err := u.UnmarshalBinary(nil)
+	assert.NotNil(t, err)
+	err = u.UnmarshalBinary(make([]byte, 6*8))
+	assert.NotNil(t, err)
+}
+
+func TestSeed(t *testing.T) {
+	t.Parallel()
+
+	var s chacha.Source
+	s.Seed(1)
+	x := s.Uint64()
+	s.Seed(1)
+	assert.Equal(t, x, s.Uint64())
+
+	// Usable with the functions in randstat.
+	n := randstat.Intn(&s, 10)
+	assert.True(t, n >= 0 && n < 10)
+}
+
+// The zero Source must not produce output.
+func TestZero(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { new(chacha.Source).Uint64() })
+	assert.Panics(t, func() { new(chacha.Source).Read(make([]byte, 1)) })
+}
+
+func benchmarkUint64(b *testing.B, rounds int) {
+	r := chacha.New([32]byte{}, rounds)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkUint64ChaCha8(b *testing.B)  { benchmarkUint64(b, 8) }
+func BenchmarkUint64ChaCha12(b *testing.B) { benchmarkUint64(b, 12) }
+func BenchmarkUint64ChaCha20(b *testing.B) { benchmarkUint64(b, 20) }
+
+func BenchmarkRead(b *testing.B) {
+	r := chacha.New([32]byte{}, 8)
+	buf := make([]byte, 4096)
+	b.SetBytes(int64(len(buf)))
+
+	for i := 0; i < b.N; i++ {
+		r.Read(buf)
+	}
+}
@@ -0,0 +1,191 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// Binomial returns a random variate from the binomial distribution:
+// the number of successes in n independent trials with success
+// probability p. n must not be negative and p must be in [0,1].
+//
+// When the expected number of successes or failures is at most 30,
+// Binomial uses inversion, which takes time proportional to that number.
+// Otherwise, it uses the BTPE algorithm of Kachitvichyanukul and Schmeiser,
+// Binomial Random Variate Generation, https://doi.org/10.1145/42372.42381,
+// which takes constant expected time.
+func Binomial(r rand.Source64, n int64, p float64) int64 {
+	switch {
+	case n < 0:
+		panic("dist.Binomial: n < 0")
+	case !(p >= 0 && p <= 1):
+		panic("dist.Binomial: p not in [0,1]")
+	case n == 0 || p == 0:
+		return 0
+	}
+
+	// Sample the smaller of the number of successes and failures.
+	if p > .5 {
+		return n - binomial(r, n, 1-p)
+	}
+	return binomial(r, n, p)
+}
+
+// binomial samples a binomial variate for p <= .5.
+func binomial(r rand.Source64, n int64, p float64) int64 {
+	if float64(n)*p <= 30 {
+		return binomialInversion(r, n, p)
+	}
+	return btpe(r, n, p)
+}
+
+func binomialInversion(r rand.Source64, n int64, p float64) int64 {
+	var (
+		q  = 1 - p
+		qn = math.Exp(float64(n) * math.Log1p(-p)) // Precise even if q rounds to 1.
+		np = float64(n) * p
+
+		// Cut off the search where the remaining mass is negligible.
+		bound = int64(math.Min(float64(n), np+10*math.Sqrt(np*q+1)))
+	)
+
+	for {
+		u := randstat.Float64(r)
+		px := qn
+		for x := int64(0); x <= bound; x++ {
+			if u <= px {
+				return x
+			}
+			u -= px
+			px *= float64(n-x) * p / (float64(x+1) * q)
+		}
+	}
+}
+
+// btpe samples a binomial variate for p <= .5 and n*p > 30.
+// The step numbers refer to the paper.
+func btpe(r rand.Source64, n int64, p float64) int64 {
+	// Step 0: set up the triangle, parallelograms and exponential tails
+	// of the majorizing function.
+	var (
+		nf   = float64(n)
+		q    = 1 - p
+		nrq  = nf * p * q
+		fm   = nf*p + p
+		m    = math.Floor(fm)
+		p1   = math.Floor(2.195*math.Sqrt(nrq)-4.6*q) + .5
+		xm   = m + .5
+		xl   = xm - p1
+		xr   = xm + p1
+		c    = .134 + 20.5/(15.3+m)
+		al   = (fm - xl) / (fm - xl*p)
+		laml = al * (1 + al/2)
+		ar   = (xr - fm) / (xr * q)
+		lamr = ar * (1 + ar/2)
+		p2   = p1 * (1 + 2*c)
+		p3   = p2 + c/laml
+		p4   = p3 + c/lamr
+	)
+
+	for {
+		u := randstat.Float64(r) * p4
+		v := randstat.Float64(r)
+		var y float64
+
+		switch {
+		case u <= p1:
+			// Step 1: triangular region, immediate acceptance.
+			return int64(math.Floor(xm - p1*v + u))
+
+		case u <= p2:
+			// Step 2: parallelograms.
+			x := xl + (u-p1)/c
+			v = v*c + 1 - math.Abs(m-x+.5)/p1
+			if v > 1 {
+				continue
+			}
+			y = math.Floor(x)
+
+		case u <= p3:
+			// Step 3: left exponential tail.
+			y = math.Floor(xl + math.Log(v)/laml)
+			if y < 0 || v == 0 {
+				continue
+			}
+			v *= (u - p2) * laml
+
+		default:
+			// Step 4: right exponential tail.
+			y = math.Floor(xr - math.Log(v)/lamr)
+			if y > nf || v == 0 {
+				continue
+			}
+			v *= (u - p3) * lamr
+		}
+
+		k := math.Abs(y - m)
+		if k <= 20 || k >= nrq/2-1 {
+			// Step 5.1: evaluate f(y)/f(m) recursively.
+			s := p / q
+			a := s * (nf + 1)
+			f := 1.0
+			if m < y {
+				for i := m + 1; i <= y; i++ {
+					f *= a/i - s
+				}
+			} else if m > y {
+				for i := y + 1; i <= m; i++ {
+					f /= a/i - s
+				}
+			}
+			if v <= f {
+				return int64(y)
+			}
+			continue
+		}
+
+		// Step 5.2: squeeze using upper and lower bounds on log(f(y)).
+		rho := (k / nrq) * ((k*(k/3+.625)+1./6)/nrq + .5)
+		t := -k * k / (2 * nrq)
+		logv := math.Log(v)
+		if logv < t-rho {
+			return int64(y)
+		}
+		if logv > t+rho {
+			continue
+		}
+
+		// Step 5.3: final acceptance test, using Stirling's formula.
+		x1 := y + 1
+		f1 := m + 1
+		z := nf + 1 - m
+		w := nf - y + 1
+		bound := xm*math.Log(f1/x1) + (nf-m+.5)*math.Log(z/w) +
+			(y-m)*math.Log(w*p/(x1*q)) +
+			stirlingTail(f1) + stirlingTail(z) + stirlingTail(x1) + stirlingTail(w)
+		if logv <= bound {
+			return int64(y)
+		}
+	}
+}
+
+// stirlingTail returns the correction term of Stirling's series
+// for log(x!), through the x^-9 term.
+func stirlingTail(x float64) float64 {
+	x2 := x * x
+	return (13680 - (462-(132-(99-140/x2)/x2)/x2)/x2) / x / 166320
+}
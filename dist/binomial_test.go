@@ -0,0 +1,106 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleInts(seed uint64, f func(r rand.Source64) int64) []int64 {
+	r := xoshiro256.New(seed)
+	x := make([]int64, n)
+	for i := range x {
+		x[i] = f(r)
+	}
+	return x
+}
+
+func toFloat(x []int64) []float64 {
+	f := make([]float64, len(x))
+	for i := range x {
+		f[i] = float64(x[i])
+	}
+	return f
+}
+
+func binomialPMF(n int64, p float64) func(int64) float64 {
+	return func(k int64) float64 {
+		if k > n {
+			return 0
+		}
+		a, _ := math.Lgamma(float64(n + 1))
+		b, _ := math.Lgamma(float64(k + 1))
+		c, _ := math.Lgamma(float64(n - k + 1))
+		return math.Exp(a - b - c + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+	}
+}
+
+func TestBinomial(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct {
+		n int64
+		p float64
+	}{
+		// Inversion, for successes and failures.
+		{1, .5}, {10, .3}, {100, .2}, {50, .9}, {1e7, 1e-6},
+		// Tiny p, for which 1-p rounds to 1.
+		{1 << 62, 1e-18},
+		// BTPE, including the squeeze for large n.
+		{100, .35}, {1000, .3}, {200, .8}, {1e6, .5}, {1e9, .01},
+	} {
+		x := sampleInts(uint64(i), func(r rand.Source64) int64 {
+			return dist.Binomial(r, c.n, c.p)
+		})
+		for _, k := range x {
+			assert.True(t, k >= 0 && k <= c.n)
+		}
+
+		mean := float64(c.n) * c.p
+		stattest.Moments(t, toFloat(x), mean, mean*(1-c.p), .05)
+		if c.n <= 1e6 {
+			stattest.Discrete(t, x, binomialPMF(c.n, c.p))
+		}
+	}
+
+	r := xoshiro256.New(1)
+	assert.EqualValues(t, 0, dist.Binomial(r, 0, .5))
+	assert.EqualValues(t, 0, dist.Binomial(r, 100, 0))
+	assert.EqualValues(t, 100, dist.Binomial(r, 100, 1))
+	assert.Panics(t, func() { dist.Binomial(r, -1, .5) })
+	assert.Panics(t, func() { dist.Binomial(r, 10, 1.5) })
+	assert.Panics(t, func() { dist.Binomial(r, 10, math.NaN()) })
+}
+
+func BenchmarkBinomial(b *testing.B) {
+	for _, c := range []struct {
+		n int64
+		p float64
+	}{{20, .5}, {1000, .01}, {1e6, .3}} {
+		b.Run(fmt.Sprint(c.n, "/", c.p), func(b *testing.B) {
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				dist.Binomial(r, c.n, c.p)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Dirichlet appends to out a random variate from the Dirichlet distribution
+// with concentration parameters alpha, i.e., len(alpha) non-negative numbers
+// that sum to one, and returns the resulting slice.
+//
+// The parameters must be positive and finite. With all parameters equal to
+// one, the result is a uniformly random point on the probability simplex,
+// which is the weight vector of the Bayesian bootstrap.
+//
+// Dirichlet normalizes a vector of gamma variates. When all parameters
+// are small, so that the gamma variates might all underflow to zero,
+// it instead uses stick-breaking with beta variates, as NumPy does.
+func Dirichlet(r rand.Source64, alpha []float64, out []float64) []float64 {
+	var maxAlpha float64
+	for _, a := range alpha {
+		if !(a > 0 && a <= math.MaxFloat64) {
+			panic("dist.Dirichlet: parameters must be positive and finite")
+		}
+		maxAlpha = math.Max(maxAlpha, a)
+	}
+	if len(alpha) == 0 {
+		return out
+	}
+
+	start := len(out)
+	for range alpha {
+		out = append(out, 0)
+	}
+	x := out[start:]
+
+	if maxAlpha < .1 {
+		// Store the suffix sums of alpha in x, then overwrite them
+		// from the front as the stick is broken.
+		var sum float64
+		for i := len(alpha) - 1; i >= 0; i-- {
+			sum += alpha[i]
+			x[i] = sum
+		}
+
+		stick := 1.0
+		for i, a := range alpha[:len(alpha)-1] {
+			x[i] = stick * Beta(r, a, x[i+1])
+			stick -= x[i]
+		}
+		x[len(x)-1] = math.Max(0, stick)
+		return out
+	}
+
+	var sum float64
+	for i, a := range alpha {
+		x[i] = stdGamma(r, a)
+		sum += x[i]
+	}
+	for i := range x {
+		x[i] /= sum
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirichlet(t *testing.T) {
+	t.Parallel()
+
+	r := xoshiro256.New(0xd1c)
+
+	for _, alpha := range [][]float64{
+		{1},
+		{1, 1, 1, 1},
+		{.5, 2, 10},
+		{.05, .01, .08, .02}, // Stick-breaking.
+		{1e-3, 1e-3},
+	} {
+		var a0 float64
+		for _, a := range alpha {
+			a0 += a
+		}
+
+		const nrounds = 20000
+		marginals := make([][]float64, len(alpha))
+		var buf []float64
+		for i := 0; i < nrounds; i++ {
+			buf = dist.Dirichlet(r, alpha, buf[:0])
+			require.Len(t, buf, len(alpha))
+
+			var sum float64
+			for j, x := range buf {
+				require.True(t, x >= 0 && x <= 1)
+				sum += x
+				marginals[j] = append(marginals[j], x)
+			}
+			require.InDelta(t, 1, sum, 1e-12)
+		}
+
+		// Marginals are beta distributed.
+		for j, a := range alpha {
+			mean := a / a0
+			variance := a * (a0 - a) / (a0 * a0 * (a0 + 1))
+			if variance > 0 {
+				stattest.Moments(t, marginals[j], mean, variance, .1)
+			}
+		}
+	}
+
+	assert.Equal(t, []float64{3}, dist.Dirichlet(r, nil, []float64{3}))
+	assert.Panics(t, func() { dist.Dirichlet(r, []float64{1, 0}, nil) })
+	assert.Panics(t, func() { dist.Dirichlet(r, []float64{math.NaN()}, nil) })
+}
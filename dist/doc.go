@@ -0,0 +1,5 @@
+// Package dist generates random variates from probability distributions.
+//
+// Like the functions in package randstat, the functions in this package
+// take their random numbers from a rand.Source64, which must not be nil.
+package dist
@@ -0,0 +1,128 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// Beta returns a random variate from the beta distribution with shape
+// parameters a and b, which must be positive.
+//
+// It uses Jöhnk's algorithm when both a and b are at most one,
+// otherwise the ratio X/(X+Y) of gamma variates.
+func Beta(r rand.Source64, a, b float64) float64 {
+	if !(a > 0 && b > 0) {
+		panic("dist.Beta: shape parameters must be positive")
+	}
+
+	if a > 1 || b > 1 {
+		x := stdGamma(r, a)
+		y := stdGamma(r, b)
+		return x / (x + y)
+	}
+
+	for {
+		u, v := randstat.Float64Open(r), randstat.Float64Open(r)
+		x, y := math.Pow(u, 1/a), math.Pow(v, 1/b)
+		xy := x + y
+		switch {
+		case xy > 1:
+			continue
+		case xy > 0:
+			return x / xy
+		}
+
+		// Both x and y underflowed. Compute the ratio in log space.
+		logx, logy := math.Log(u)/a, math.Log(v)/b
+		logm := math.Max(logx, logy)
+		logx -= logm
+		logy -= logm
+		return math.Exp(logx - math.Log(math.Exp(logx)+math.Exp(logy)))
+	}
+}
+
+// ChiSquared returns a random variate from the chi-squared distribution
+// with k degrees of freedom. k must be positive.
+func ChiSquared(r rand.Source64, k float64) float64 {
+	if !(k > 0) {
+		panic("dist.ChiSquared: degrees of freedom must be positive")
+	}
+	return 2 * stdGamma(r, k/2)
+}
+
+// F returns a random variate from the F distribution with d1 and d2
+// degrees of freedom, which must be positive.
+func F(r rand.Source64, d1, d2 float64) float64 {
+	if !(d1 > 0 && d2 > 0) {
+		panic("dist.F: degrees of freedom must be positive")
+	}
+	x := stdGamma(r, d1/2) / d1
+	y := stdGamma(r, d2/2) / d2
+	return x / y
+}
+
+// Gamma returns a random variate from the gamma distribution with the given
+// shape and scale parameters, which must be positive.
+//
+// It uses the algorithm of Marsaglia and Tsang, A Simple Method for
+// Generating Gamma Variables, https://doi.org/10.1145/358407.358414,
+// including their boost for shape < 1.
+func Gamma(r rand.Source64, shape, scale float64) float64 {
+	if !(shape > 0 && scale > 0) {
+		panic("dist.Gamma: shape and scale must be positive")
+	}
+	return scale * stdGamma(r, shape)
+}
+
+// StudentT returns a random variate from Student's t distribution
+// with nu degrees of freedom. nu must be positive.
+func StudentT(r rand.Source64, nu float64) float64 {
+	if !(nu > 0) {
+		panic("dist.StudentT: degrees of freedom must be positive")
+	}
+	z := randstat.Normal(r, 0, 1)
+	return z / math.Sqrt(stdGamma(r, nu/2)/(nu/2))
+}
+
+// stdGamma returns a gamma variate with scale 1.
+func stdGamma(r rand.Source64, shape float64) float64 {
+	if shape < 1 {
+		// If X ~ Gamma(shape+1) and U ~ Uniform(0,1),
+		// then X*U^(1/shape) ~ Gamma(shape). For small shape, the result
+		// depends on the smallest values of U, so use all the precision
+		// that a float64 has to offer.
+		x := stdGamma(r, shape+1)
+		return math.Exp(math.Log(x) + math.Log(randstat.Float64Dense(r))/shape)
+	}
+
+	d := shape - 1./3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := randstat.Normal(r, 0, 1)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+
+		u := randstat.Float64Open(r)
+		x2 := x * x
+		if u < 1-.0331*x2*x2 || math.Log(u) < x2/2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const n = 100000
+
+func sample(seed uint64, f func(r rand.Source64) float64) []float64 {
+	r := xoshiro256.New(seed)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = f(r)
+	}
+	return x
+}
+
+func TestBeta(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct{ a, b float64 }{
+		{.01, .02}, {.5, .5}, {.3, 1}, {1, 1}, {2, 5}, {.5, 40}, {100, 200},
+	} {
+		x := sample(uint64(i), func(r rand.Source64) float64 {
+			return dist.Beta(r, c.a, c.b)
+		})
+		for _, xi := range x {
+			assert.True(t, xi >= 0 && xi <= 1)
+		}
+
+		s := c.a + c.b
+		stattest.Moments(t, x, c.a/s, c.a*c.b/(s*s*(s+1)), .05)
+
+		if c.b == 1 {
+			stattest.KS(t, x, func(x float64) float64 { return math.Pow(x, c.a) })
+		}
+	}
+
+	assert.Panics(t, func() { dist.Beta(xoshiro256.New(1), 0, 1) })
+	assert.Panics(t, func() { dist.Beta(xoshiro256.New(1), 1, math.NaN()) })
+}
+
+func TestChiSquared(t *testing.T) {
+	t.Parallel()
+
+	for i, k := range []float64{.5, 1, 2, 7.5, 1000} {
+		x := sample(uint64(i), func(r rand.Source64) float64 {
+			return dist.ChiSquared(r, k)
+		})
+		stattest.Moments(t, x, k, 2*k, .05)
+
+		if k == 2 {
+			stattest.KS(t, x, func(x float64) float64 { return -math.Expm1(-x / 2) })
+		}
+	}
+
+	assert.Panics(t, func() { dist.ChiSquared(xoshiro256.New(1), -1) })
+}
+
+func TestF(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct{ d1, d2 float64 }{
+		{2, 10}, {5, 20}, {30, 50},
+	} {
+		x := sample(uint64(i), func(r rand.Source64) float64 {
+			return dist.F(r, c.d1, c.d2)
+		})
+
+		d1, d2 := c.d1, c.d2
+		mean := d2 / (d2 - 2)
+		variance := 2 * d2 * d2 * (d1 + d2 - 2) / (d1 * (d2 - 2) * (d2 - 2) * (d2 - 4))
+		stattest.Moments(t, x, mean, variance, .1)
+	}
+
+	assert.Panics(t, func() { dist.F(xoshiro256.New(1), 1, 0) })
+}
+
+func TestGamma(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct{ shape, scale float64 }{
+		{.01, 1}, {.2, 3}, {1, 1}, {1, .5}, {2.5, 1}, {10, 2}, {1e4, 1e-3},
+	} {
+		x := sample(uint64(i), func(r rand.Source64) float64 {
+			return dist.Gamma(r, c.shape, c.scale)
+		})
+		for _, xi := range x {
+			assert.True(t, xi >= 0)
+		}
+
+		// The sample variance has relative standard error
+		// sqrt((kurtosis+2)/n), with excess kurtosis 6/shape.
+		eps := math.Max(.05, 5*math.Sqrt((6/c.shape+2)/n))
+		stattest.Moments(t, x, c.shape*c.scale, c.shape*c.scale*c.scale, eps)
+
+		if c.shape == 1 {
+			stattest.KS(t, x, func(x float64) float64 { return -math.Expm1(-x / c.scale) })
+		}
+	}
+
+	assert.Panics(t, func() { dist.Gamma(xoshiro256.New(1), 0, 1) })
+	assert.Panics(t, func() { dist.Gamma(xoshiro256.New(1), 1, -1) })
+}
+
+func TestStudentT(t *testing.T) {
+	t.Parallel()
+
+	// Cauchy distribution.
+	x := sample(1, func(r rand.Source64) float64 { return dist.StudentT(r, 1) })
+	stattest.KS(t, x, func(x float64) float64 { return .5 + math.Atan(x)/math.Pi })
+
+	// Two degrees of freedom.
+	x = sample(2, func(r rand.Source64) float64 { return dist.StudentT(r, 2) })
+	stattest.KS(t, x, func(x float64) float64 { return .5 + x/(2*math.Sqrt(2+x*x)) })
+
+	for i, nu := range []float64{10, 100} {
+		x := sample(uint64(3+i), func(r rand.Source64) float64 {
+			return dist.StudentT(r, nu)
+		})
+		stattest.Moments(t, x, 0, nu/(nu-2), .05)
+	}
+
+	assert.Panics(t, func() { dist.StudentT(xoshiro256.New(1), 0) })
+}
+
+func BenchmarkGamma(b *testing.B) {
+	for _, shape := range []float64{.5, 1, 10} {
+		b.Run(fmt.Sprint(shape), func(b *testing.B) {
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				dist.Gamma(r, shape, 1)
+			}
+		})
+	}
+}
+
+func BenchmarkBeta(b *testing.B) {
+	for _, c := range []struct{ a, b float64 }{{.5, .5}, {2, 5}} {
+		b.Run(fmt.Sprint(c.a, "/", c.b), func(b *testing.B) {
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				dist.Beta(r, c.a, c.b)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// Hypergeometric returns a random variate from the hypergeometric
+// distribution: the number of good items in a simple random sample of size
+// draws, taken without replacement from a population of good+bad items.
+//
+// The arguments must not be negative, good+bad must not overflow
+// and draws must not exceed good+bad.
+//
+// For fewer than ten draws (or ten non-draws), Hypergeometric simulates
+// the draws. Otherwise, it uses the ratio-of-uniforms algorithm HRUA of
+// Stadlober, The ratio of uniforms approach for generating discrete random
+// variates, https://doi.org/10.1016/0377-0427(90)90349-5, as modified
+// for NumPy, which takes constant expected time.
+func Hypergeometric(r rand.Source64, good, bad, draws int64) int64 {
+	switch {
+	case good < 0 || bad < 0 || draws < 0:
+		panic("dist.Hypergeometric: negative argument")
+	case good+bad < 0:
+		panic("dist.Hypergeometric: population too large")
+	case draws > good+bad:
+		panic("dist.Hypergeometric: draws > good+bad")
+	}
+	return hypergeometric(r, good, bad, draws)
+}
+
+func hypergeometric(r rand.Source64, good, bad, draws int64) int64 {
+	if draws >= 10 && draws <= good+bad-10 {
+		return hrua(r, good, bad, draws)
+	}
+	return hypergeometricSimulate(r, good, bad, draws)
+}
+
+func hypergeometricSimulate(r rand.Source64, good, bad, draws int64) int64 {
+	total := good + bad
+
+	// Simulate the smaller of the draws and the non-draws.
+	n := draws
+	if draws > total/2 {
+		n = total - draws
+	}
+
+	remaining, remgood := total, good
+	for ; n > 0 && remgood > 0 && remaining > remgood; n-- {
+		if randstat.Int63n(r, remaining) < remgood {
+			remgood--
+		}
+		remaining--
+	}
+	if remaining == remgood {
+		// Only good items are left.
+		remgood -= n
+	}
+
+	if draws > total/2 {
+		return remgood
+	}
+	return good - remgood
+}
+
+const (
+	hruaD1 = 1.7155277699214135 // 2*sqrt(2/e)
+	hruaD2 = 0.8989161620588988 // 3 - 2*sqrt(3/e)
+)
+
+func hrua(r rand.Source64, good, bad, draws int64) int64 {
+	var (
+		total = good + bad
+		n     = min64(draws, total-draws)
+		mingb = min64(good, bad)
+		maxgb = good + bad - mingb
+
+		p        = float64(mingb) / float64(total)
+		q        = float64(maxgb) / float64(total)
+		mu       = float64(n) * p
+		a        = mu + .5
+		variance = float64(total-n) * float64(n) * p * q / float64(total-1)
+		c        = math.Sqrt(variance + .5)
+		h        = hruaD1*c + hruaD2
+		m        = int64(math.Floor(float64(n+1) * float64(mingb+1) / float64(total+2)))
+		g        = logFactorial(m) + logFactorial(mingb-m) + logFactorial(n-m) + logFactorial(maxgb-n+m)
+		bound    = math.Min(float64(min64(n, mingb)+1), math.Floor(a+16*c))
+	)
+
+	var k int64
+	for {
+		u := randstat.Float64(r)
+		v := randstat.Float64(r)
+		x := a + h*(v-.5)/u
+		if x < 0 || x >= bound {
+			continue
+		}
+
+		k = int64(math.Floor(x))
+		t := g - (logFactorial(k) + logFactorial(mingb-k) +
+			logFactorial(n-k) + logFactorial(maxgb-n+k))
+
+		if u*(4-u)-3 <= t {
+			break // Fast acceptance.
+		}
+		if u*(u-t) >= 1 {
+			continue // Fast rejection.
+		}
+		if 2*math.Log(u) <= t {
+			break
+		}
+	}
+
+	if good > bad {
+		k = n - k
+	}
+	if n < draws {
+		k = good - k
+	}
+	return k
+}
+
+func logFactorial(k int64) float64 {
+	lg, _ := math.Lgamma(float64(k) + 1)
+	return lg
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// MultivariateHypergeometric returns the numbers of items from each stratum
+// in a simple random sample of size draws, taken without replacement from
+// a population with counts[i] items in stratum i.
+//
+// The counts must not be negative, their sum must not overflow
+// and draws must not exceed it. The time complexity is O(len(counts)),
+// independent of the population size.
+func MultivariateHypergeometric(r rand.Source64, counts []int64, draws int64) []int64 {
+	var total int64
+	for _, c := range counts {
+		if c < 0 {
+			panic("dist.MultivariateHypergeometric: negative count")
+		}
+		total += c
+		if total < 0 {
+			panic("dist.MultivariateHypergeometric: population too large")
+		}
+	}
+	switch {
+	case draws < 0:
+		panic("dist.MultivariateHypergeometric: draws < 0")
+	case draws > total:
+		panic("dist.MultivariateHypergeometric: draws > total count")
+	}
+
+	// Sample the smaller of the draws and the non-draws, stratum by stratum,
+	// each time from the strata not yet considered.
+	n := draws
+	complement := draws > total/2
+	if complement {
+		n = total - draws
+	}
+
+	sample := make([]int64, len(counts))
+	remaining := total
+	for i := 0; n > 0 && i < len(counts)-1; i++ {
+		remaining -= counts[i]
+		k := hypergeometric(r, counts[i], remaining, n)
+		sample[i] = k
+		n -= k
+	}
+	if n > 0 {
+		sample[len(sample)-1] = n
+	}
+
+	if complement {
+		for i := range sample {
+			sample[i] = counts[i] - sample[i]
+		}
+	}
+	return sample
+}
@@ -0,0 +1,137 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lchoose(n, k int64) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+func hypergeometricPMF(good, bad, draws int64) func(int64) float64 {
+	return func(k int64) float64 {
+		if k > good || k > draws || draws-k > bad {
+			return 0
+		}
+		return math.Exp(lchoose(good, k) + lchoose(bad, draws-k) - lchoose(good+bad, draws))
+	}
+}
+
+func TestHypergeometric(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct{ good, bad, draws int64 }{
+		// Simulation.
+		{5, 5, 3}, {100, 20, 5}, {1000, 10, 1005}, {0, 10, 5}, {10, 0, 5},
+		// HRUA.
+		{50, 50, 30}, {20, 1000, 500}, {1000, 20, 500}, {500, 300, 700},
+		{1e6, 1e7, 1e5}, {1e12, 1e12, 1e9},
+	} {
+		x := sampleInts(uint64(i), func(r rand.Source64) int64 {
+			return dist.Hypergeometric(r, c.good, c.bad, c.draws)
+		})
+
+		total := float64(c.good + c.bad)
+		p := float64(c.good) / total
+		mean := float64(c.draws) * p
+		variance := mean * (1 - p) * (total - float64(c.draws)) / (total - 1)
+		if variance > 0 {
+			stattest.Moments(t, toFloat(x), mean, variance, .05)
+		}
+		if c.good+c.bad <= 1e7 {
+			stattest.Discrete(t, x, hypergeometricPMF(c.good, c.bad, c.draws))
+		}
+	}
+
+	r := xoshiro256.New(1)
+	assert.EqualValues(t, 7, dist.Hypergeometric(r, 7, 3, 10))
+	assert.Panics(t, func() { dist.Hypergeometric(r, -1, 3, 1) })
+	assert.Panics(t, func() { dist.Hypergeometric(r, 7, 3, 11) })
+	assert.Panics(t, func() { dist.Hypergeometric(r, math.MaxInt64, 3, 1) })
+}
+
+func TestMultivariateHypergeometric(t *testing.T) {
+	t.Parallel()
+
+	r := xoshiro256.New(42)
+
+	for _, c := range []struct {
+		counts []int64
+		draws  int64
+	}{
+		{[]int64{}, 0},
+		{[]int64{10}, 4},
+		{[]int64{5, 0, 10, 3}, 7},
+		{[]int64{5, 0, 10, 3}, 15},
+		{[]int64{1e9, 2e9, 3e9, 1, 4e9}, 5e9},
+	} {
+		var total int64
+		for _, count := range c.counts {
+			total += count
+		}
+
+		const nrounds = 10000
+		sums := make([]float64, len(c.counts))
+		for i := 0; i < nrounds; i++ {
+			sample := dist.MultivariateHypergeometric(r, c.counts, c.draws)
+			require.Len(t, sample, len(c.counts))
+
+			var n int64
+			for j, k := range sample {
+				require.True(t, k >= 0 && k <= c.counts[j])
+				n += k
+				sums[j] += float64(k)
+			}
+			require.Equal(t, c.draws, n)
+		}
+
+		// Marginals are hypergeometric.
+		for j, count := range c.counts {
+			mean := float64(c.draws) * float64(count) / float64(total)
+			p := float64(count) / float64(total)
+			sd := math.Sqrt(mean * (1 - p) / nrounds)
+			assert.InDelta(t, mean, sums[j]/nrounds, 5*sd+1e-9)
+		}
+	}
+
+	assert.Panics(t, func() { dist.MultivariateHypergeometric(r, []int64{1, -1}, 0) })
+	assert.Panics(t, func() { dist.MultivariateHypergeometric(r, []int64{1, 1}, 3) })
+}
+
+func BenchmarkHypergeometric(b *testing.B) {
+	for _, c := range []struct{ good, bad, draws int64 }{
+		{100, 100, 5}, {1e6, 1e9, 1e5},
+	} {
+		b.Run(fmt.Sprint(c.good, "/", c.bad, "/", c.draws), func(b *testing.B) {
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				dist.Hypergeometric(r, c.good, c.bad, c.draws)
+			}
+		})
+	}
+}
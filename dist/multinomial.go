@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat/internal/sums"
+)
+
+// Multinomial appends to out a random variate from the multinomial
+// distribution, i.e., the number of times each of len(probs) outcomes occurs
+// in n independent trials, and returns the resulting slice.
+//
+// Outcome i has probability proportional to probs[i]: the probabilities
+// are normalized, so they need not sum to one, but they must be finite and
+// non-negative and not all zero. n must not be negative.
+//
+// Multinomial draws a binomial variate per outcome, conditional on the
+// outcomes before it, so its expected time complexity is O(len(probs)).
+func Multinomial(r rand.Source64, n int64, probs []float64, out []int64) []int64 {
+	if n < 0 {
+		panic("dist.Multinomial: n < 0")
+	}
+
+	var rem sums.Neumaier // Remaining probability mass.
+	for _, p := range probs {
+		if !(p >= 0 && p <= math.MaxFloat64) {
+			panic("dist.Multinomial: probabilities must be finite and non-negative")
+		}
+		rem.Add(p)
+	}
+	switch {
+	case len(probs) == 0:
+		return out
+	case !(rem.Value() > 0):
+		panic("dist.Multinomial: probabilities are all zero")
+	}
+
+	for _, p := range probs[:len(probs)-1] {
+		var k int64
+		if n > 0 && p > 0 {
+			q := 1.0
+			if remp := rem.Value(); p < remp {
+				q = p / remp
+			}
+			k = Binomial(r, n, q)
+		}
+		out = append(out, k)
+		n -= k
+		rem.Add(-p)
+	}
+	return append(out, n)
+}
@@ -0,0 +1,84 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultinomial(t *testing.T) {
+	t.Parallel()
+
+	r := xoshiro256.New(0x3ab)
+
+	for _, c := range []struct {
+		n     int64
+		probs []float64
+	}{
+		{10, []float64{1}},
+		{0, []float64{.5, .5}},
+		{100, []float64{.2, .3, .5}},
+		{1000, []float64{1, 0, 2, 0, 0, 7}}, // Not normalized.
+		{1e9, []float64{.25, .25, .25, .25}},
+		{50, []float64{1e-9, 1, 1e-9}},
+	} {
+		var total float64
+		for _, p := range c.probs {
+			total += p
+		}
+
+		const nrounds = 10000
+		marginals := make([][]float64, len(c.probs))
+		buf := []int64{-1}
+		for i := 0; i < nrounds; i++ {
+			buf = dist.Multinomial(r, c.n, c.probs, buf[:1])
+			require.Len(t, buf, 1+len(c.probs))
+			require.EqualValues(t, -1, buf[0])
+
+			var sum int64
+			for j, k := range buf[1:] {
+				require.True(t, k >= 0)
+				if c.probs[j] == 0 {
+					require.Zero(t, k)
+				}
+				sum += k
+				marginals[j] = append(marginals[j], float64(k))
+			}
+			require.Equal(t, c.n, sum)
+		}
+
+		// Marginals are binomial.
+		for j, p := range c.probs {
+			p /= total
+			mean := float64(c.n) * p
+			// Skip marginals that are almost constant.
+			if variance := mean * (1 - p); variance > .1 {
+				stattest.Moments(t, marginals[j], mean, variance, .1)
+			}
+		}
+	}
+
+	assert.Empty(t, dist.Multinomial(r, 10, nil, nil))
+	assert.Panics(t, func() { dist.Multinomial(r, -1, []float64{1}, nil) })
+	assert.Panics(t, func() { dist.Multinomial(r, 1, []float64{0, 0}, nil) })
+	assert.Panics(t, func() { dist.Multinomial(r, 1, []float64{1, -1}, nil) })
+	assert.Panics(t, func() { dist.Multinomial(r, 1, []float64{1, math.Inf(1)}, nil) })
+}
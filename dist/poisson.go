@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// Largest lambda for which Poisson variates fit in an int64 with
+// overwhelming probability: MaxInt64 - 10*sqrt(MaxInt64), as in NumPy.
+const maxPoissonLambda = 9.223372006484771e18
+
+// Poisson returns a random variate from the Poisson distribution with
+// mean lambda, which must be non-negative and at most about 9.2e18.
+//
+// For lambda < 10, Poisson uses inversion, which takes time proportional
+// to lambda. Otherwise, it uses Hörmann's PTRS algorithm, The transformed
+// rejection method for generating Poisson random variables,
+// https://doi.org/10.1016/0167-6687(93)90997-4, which takes constant
+// expected time.
+func Poisson(r rand.Source64, lambda float64) int64 {
+	switch {
+	case !(lambda >= 0 && lambda <= maxPoissonLambda):
+		panic("dist.Poisson: lambda out of range")
+	case lambda == 0:
+		return 0
+	case lambda < 10:
+		return poissonInversion(r, lambda)
+	}
+	return ptrs(r, lambda)
+}
+
+func poissonInversion(r rand.Source64, lambda float64) int64 {
+	var (
+		p0 = math.Exp(-lambda)
+		// Cut off the search where the remaining mass is negligible.
+		bound = int64(lambda + 10*math.Sqrt(lambda) + 10)
+	)
+
+	for {
+		u := randstat.Float64(r)
+		p := p0
+		for k := int64(0); k <= bound; k++ {
+			if u <= p {
+				return k
+			}
+			u -= p
+			p *= lambda / float64(k+1)
+		}
+	}
+}
+
+func ptrs(r rand.Source64, lambda float64) int64 {
+	var (
+		slam     = math.Sqrt(lambda)
+		loglam   = math.Log(lambda)
+		b        = .931 + 2.53*slam
+		a        = -.059 + .02483*b
+		invalpha = 1.1239 + 1.1328/(b-3.4)
+		vr       = .9277 - 3.6224/(b-2)
+	)
+
+	for {
+		u := randstat.Float64(r) - .5
+		v := randstat.Float64(r)
+		us := .5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + .43)
+
+		if us >= .07 && v <= vr {
+			return int64(k)
+		}
+		if k < 0 || (us < .013 && v > us) {
+			continue
+		}
+
+		lgam, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -lambda+k*loglam-lgam {
+			return int64(k)
+		}
+	}
+}
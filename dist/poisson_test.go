@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func poissonPMF(lambda float64) func(int64) float64 {
+	return func(k int64) float64 {
+		lg, _ := math.Lgamma(float64(k + 1))
+		return math.Exp(float64(k)*math.Log(lambda) - lambda - lg)
+	}
+}
+
+func TestPoisson(t *testing.T) {
+	t.Parallel()
+
+	for i, lambda := range []float64{.01, .5, 3, 9.99, 10, 25, 1000, 1e6, 1e12} {
+		x := sampleInts(uint64(i), func(r rand.Source64) int64 {
+			return dist.Poisson(r, lambda)
+		})
+
+		stattest.Moments(t, toFloat(x), lambda, lambda, .05)
+		if lambda <= 1e6 {
+			stattest.Discrete(t, x, poissonPMF(lambda))
+		}
+	}
+
+	r := xoshiro256.New(1)
+	assert.EqualValues(t, 0, dist.Poisson(r, 0))
+	assert.Panics(t, func() { dist.Poisson(r, -1) })
+	assert.Panics(t, func() { dist.Poisson(r, math.Inf(1)) })
+	assert.Panics(t, func() { dist.Poisson(r, math.NaN()) })
+}
+
+func BenchmarkPoisson(b *testing.B) {
+	for _, lambda := range []float64{1, 20, 1e6} {
+		b.Run(fmt.Sprint(lambda), func(b *testing.B) {
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				dist.Poisson(r, lambda)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// A Zipf generates random variates from a Zipf distribution on [1,n]:
+// the probability of k is proportional to k^-s.
+//
+// Unlike rand.Zipf, it supports any exponent s > 0, including s <= 1,
+// for which the distribution is only defined on a finite range.
+//
+// Zipf implements the rejection-inversion algorithm of Hörmann and
+// Derflinger, Rejection-inversion to generate variates from monotone
+// discrete distributions, https://doi.org/10.1145/235025.235029,
+// which takes constant expected time.
+//
+// A Zipf is immutable, so it may be used by multiple goroutines
+// simultaneously, provided they use different random sources.
+type Zipf struct {
+	n      int64
+	s      float64 // Exponent.
+	hx1    float64 // hIntegral(1.5) - 1.
+	hn     float64 // hIntegral(n + .5).
+	accept float64 // Values of k - x below this are always accepted.
+}
+
+// NewZipf returns a Zipf that generates values in [1,n] with
+// exponent s. It panics if s <= 0 or n < 1.
+func NewZipf(s float64, n int64) *Zipf {
+	switch {
+	case !(s > 0 && s <= math.MaxFloat64):
+		panic("dist.NewZipf: exponent must be positive and finite")
+	case n < 1:
+		panic("dist.NewZipf: n < 1")
+	}
+
+	z := &Zipf{n: n, s: s}
+	z.hx1 = z.hIntegral(1.5) - 1
+	z.hn = z.hIntegral(float64(n) + .5)
+	z.accept = 2 - z.hIntegralInverse(z.hIntegral(2.5)-z.h(2))
+	return z
+}
+
+// Sample returns a random variate. r must not be nil.
+func (z *Zipf) Sample(r rand.Source64) int64 {
+	for {
+		// u is uniform in (hIntegral(1.5) - 1, hIntegral(n + .5)].
+		u := z.hn + randstat.Float64(r)*(z.hx1-z.hn)
+		x := z.hIntegralInverse(u)
+
+		k := int64(x + .5)
+		if k < 1 {
+			k = 1
+		} else if k > z.n {
+			k = z.n
+		}
+
+		if float64(k)-x <= z.accept || u >= z.hIntegral(float64(k)+.5)-z.h(float64(k)) {
+			return k
+		}
+	}
+}
+
+// h is the unnormalized density, x^-s.
+func (z *Zipf) h(x float64) float64 { return math.Exp(-z.s * math.Log(x)) }
+
+// hIntegral is an antiderivative of h: (x^(1-s) - 1) / (1-s),
+// or log(x) if s == 1.
+func (z *Zipf) hIntegral(x float64) float64 {
+	logx := math.Log(x)
+	return expm1x((1-z.s)*logx) * logx
+}
+
+// hIntegralInverse is the inverse of hIntegral.
+func (z *Zipf) hIntegralInverse(x float64) float64 {
+	t := x * (1 - z.s)
+	if t < -1 {
+		// Numerical error can take t slightly below -1.
+		t = -1
+	}
+	return math.Exp(log1px(t) * x)
+}
+
+// log1px returns log(1+x)/x, with the limit 1 at x == 0.
+func log1px(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Log1p(x) / x
+	}
+	return 1 - x*(.5-x*(1./3-.25*x))
+}
+
+// expm1x returns (exp(x)-1)/x, with the limit 1 at x == 0.
+func expm1x(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Expm1(x) / x
+	}
+	return 1 + x*.5*(1+x/3*(1+.25*x))
+}
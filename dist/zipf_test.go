@@ -0,0 +1,99 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/dist"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func zipfPMF(s float64, n int64) func(int64) float64 {
+	var norm float64
+	for k := n; k >= 1; k-- {
+		norm += math.Pow(float64(k), -s)
+	}
+	return func(k int64) float64 {
+		if k < 1 || k > n {
+			return 0
+		}
+		return math.Pow(float64(k), -s) / norm
+	}
+}
+
+func TestZipf(t *testing.T) {
+	t.Parallel()
+
+	for i, c := range []struct {
+		s float64
+		n int64
+	}{
+		{.1, 10}, {.5, 1000}, {.99, 100}, {1, 1}, {1, 2}, {1, 1000},
+		{1.01, 50}, {1.5, 1e5}, {2, 10}, {3, 1e6}, {10, 1e3},
+	} {
+		z := dist.NewZipf(c.s, c.n)
+		x := sampleInts(uint64(i), func(r rand.Source64) int64 {
+			return z.Sample(r)
+		})
+		for _, k := range x {
+			assert.True(t, k >= 1 && k <= c.n)
+		}
+		stattest.Discrete(t, x, zipfPMF(c.s, c.n))
+	}
+
+	// For large n and s = 2, the probability of one is close to 6/π².
+	z := dist.NewZipf(2, math.MaxInt64)
+	x := sampleInts(20, func(r rand.Source64) int64 { return z.Sample(r) })
+	ones := 0
+	for _, k := range x {
+		assert.True(t, k >= 1)
+		if k == 1 {
+			ones++
+		}
+	}
+	p := 6 / (math.Pi * math.Pi)
+	assert.InDelta(t, p, float64(ones)/n, 5*math.Sqrt(p*(1-p)/n))
+
+	assert.Panics(t, func() { dist.NewZipf(0, 10) })
+	assert.Panics(t, func() { dist.NewZipf(-1, 10) })
+	assert.Panics(t, func() { dist.NewZipf(math.NaN(), 10) })
+	assert.Panics(t, func() { dist.NewZipf(math.Inf(1), 10) })
+	assert.Panics(t, func() { dist.NewZipf(1, 0) })
+}
+
+func BenchmarkZipf(b *testing.B) {
+	for _, s := range []float64{.8, 1, 1.2, 2} {
+		b.Run(fmt.Sprint(s), func(b *testing.B) {
+			z := dist.NewZipf(s, 1e6)
+			r := xoshiro256.New(1)
+			for i := 0; i < b.N; i++ {
+				z.Sample(r)
+			}
+		})
+	}
+
+	// For comparison, only supports s > 1.
+	b.Run("math/rand", func(b *testing.B) {
+		z := rand.NewZipf(rand.New(xoshiro256.New(1)), 1.2, 1, 1e6-1)
+		for i := 0; i < b.N; i++ {
+			z.Uint64()
+		}
+	})
+}
@@ -17,6 +17,10 @@
 // from their Source. Because they use different algorithms, they produce
 // different sequences of random numbers.
 //
+// The functions take math/rand Sources. With Go 1.22 or later, FromV2 adapts
+// a math/rand/v2 Source for use with them, and Uint64N, Uint32N and N work
+// on math/rand/v2 Sources directly.
+//
 // The subpackages provide various random number generators and sampling
 // algorithms.
 package randstat
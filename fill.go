@@ -0,0 +1,163 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package randstat
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+
+	"github.com/greatroar/randstat/splitmix64"
+	"github.com/greatroar/randstat/xoshiro256"
+)
+
+// Number of values that the Fill functions request at a time
+// from a Source with a FillUint64 method.
+const fillChunk = 64
+
+// bulkFill returns the FillUint64 method of r, if r is one of the Source
+// types known to have one. These fill slices with the generator state
+// kept in registers, which is much faster than calling r.Uint64 through
+// an interface for every value.
+func bulkFill(r rand.Source64) func([]uint64) {
+	switch src := r.(type) {
+	case *splitmix64.Source:
+		return src.FillUint64
+	case *xoshiro256.Source:
+		return src.FillUint64
+	}
+	return nil
+}
+
+// prefix returns the first n elements of buf, or all of buf if n is larger.
+func prefix(buf []uint64, n int) []uint64 {
+	if n < len(buf) {
+		buf = buf[:n]
+	}
+	return buf
+}
+
+// Read fills p with random bytes. It always returns len(p), nil.
+//
+// Every eight bytes of p are filled with a value from r.Uint64,
+// in little-endian order. A final partial group of bytes consumes
+// an entire value. r must not be nil.
+func Read(r rand.Source64, p []byte) (n int, err error) {
+	n = len(p)
+
+	if fill := bulkFill(r); fill != nil {
+		var buf [fillChunk]uint64
+		for len(p) >= 8 {
+			b := prefix(buf[:], len(p)/8)
+			fill(b)
+			for _, x := range b {
+				binary.LittleEndian.PutUint64(p, x)
+				p = p[8:]
+			}
+		}
+	} else {
+		for ; len(p) >= 8; p = p[8:] {
+			binary.LittleEndian.PutUint64(p, r.Uint64())
+		}
+	}
+
+	if len(p) > 0 {
+		x := r.Uint64()
+		for i := range p {
+			p[i] = byte(x)
+			x >>= 8
+		}
+	}
+	return n, nil
+}
+
+// FillFloat64 fills dst with pseudo-random numbers in the interval [0,1).
+//
+// It produces the same numbers as calling Float64 len(dst) times,
+// provided r.Int63 returns the upper 63 bits of r.Uint64, as it does
+// for the generators in this module. r must not be nil.
+func FillFloat64(r rand.Source64, dst []float64) {
+	fill := bulkFill(r)
+	if fill == nil {
+		for i := range dst {
+			dst[i] = Float64(r)
+		}
+		return
+	}
+
+	var buf [fillChunk]uint64
+	for len(dst) > 0 {
+		b := prefix(buf[:], len(dst))
+		fill(b)
+		for i, x := range b {
+			dst[i] = float64(x>>11) * machineEpsilon
+		}
+		dst = dst[len(b):]
+	}
+}
+
+// FillIntn fills dst with uniformly random integers from the range [0,n).
+//
+// It produces the same numbers as calling Intn len(dst) times.
+// n must be greater than 0. r must not be nil.
+func FillIntn(r rand.Source64, dst []int, n int) {
+	if n <= 0 {
+		panic("randstat.FillIntn: n <= 0")
+	}
+
+	fill := bulkFill(r)
+	if fill == nil {
+		for i := range dst {
+			dst[i] = Intn(r, n)
+		}
+		return
+	}
+
+	// The algorithm of Int63n, with the threshold computed in advance.
+	u := uint64(n)
+	thresh := (-u) % u
+
+	// Every output consumes at least one value, so we never request more
+	// values from r than Intn would.
+	var (
+		buf [fillChunk]uint64
+		b   []uint64
+	)
+	for i := range dst {
+		for {
+			if len(b) == 0 {
+				b = prefix(buf[:], len(dst)-i)
+				fill(b)
+			}
+			hi, lo := bits.Mul64(b[0], u)
+			b = b[1:]
+			if lo >= thresh {
+				dst[i] = int(hi)
+				break
+			}
+		}
+	}
+}
+
+// FillUint64 fills dst with pseudo-random 64-bit values from r.
+// It is equivalent to, but may be faster than, calling r.Uint64
+// len(dst) times. r must not be nil.
+func FillUint64(r rand.Source64, dst []uint64) {
+	if fill := bulkFill(r); fill != nil {
+		fill(dst)
+		return
+	}
+	for i := range dst {
+		dst[i] = r.Uint64()
+	}
+}
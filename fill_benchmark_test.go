@@ -0,0 +1,78 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package randstat_test
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/xoshiro256"
+)
+
+const fillBenchLen = 4096
+
+func benchmarkFill(b *testing.B, fill func(rand.Source64)) {
+	r := xoshiro256.New(0)
+	b.SetBytes(8 * fillBenchLen)
+
+	for i := 0; i < b.N; i++ {
+		fill(r)
+	}
+}
+
+var (
+	benchBytes   = make([]byte, 8*fillBenchLen)
+	benchFloat64 = make([]float64, fillBenchLen)
+	benchInts    = make([]int, fillBenchLen)
+	benchUint64  = make([]uint64, fillBenchLen)
+)
+
+func loopRead(r rand.Source64) {
+	for i := range benchUint64 {
+		binary.LittleEndian.PutUint64(benchBytes[8*i:], r.Uint64())
+	}
+}
+
+func loopFloat64(r rand.Source64) {
+	for i := range benchFloat64 {
+		benchFloat64[i] = randstat.Float64(r)
+	}
+}
+
+func loopIntn(r rand.Source64) {
+	for i := range benchInts {
+		benchInts[i] = randstat.Intn(r, 1000)
+	}
+}
+
+func loopUint64(r rand.Source64) {
+	for i := range benchUint64 {
+		benchUint64[i] = r.Uint64()
+	}
+}
+
+func fillRead(r rand.Source64)    { randstat.Read(r, benchBytes) }
+func fillFloat64(r rand.Source64) { randstat.FillFloat64(r, benchFloat64) }
+func fillIntn(r rand.Source64)    { randstat.FillIntn(r, benchInts, 1000) }
+func fillUint64(r rand.Source64)  { randstat.FillUint64(r, benchUint64) }
+
+func BenchmarkReadLoop(b *testing.B)        { benchmarkFill(b, loopRead) }
+func BenchmarkReadFill(b *testing.B)        { benchmarkFill(b, fillRead) }
+func BenchmarkFillFloat64Loop(b *testing.B) { benchmarkFill(b, loopFloat64) }
+func BenchmarkFillFloat64(b *testing.B)     { benchmarkFill(b, fillFloat64) }
+func BenchmarkFillIntnLoop(b *testing.B)    { benchmarkFill(b, loopIntn) }
+func BenchmarkFillIntn(b *testing.B)        { benchmarkFill(b, fillIntn) }
+func BenchmarkFillUint64Loop(b *testing.B)  { benchmarkFill(b, loopUint64) }
+func BenchmarkFillUint64(b *testing.B)      { benchmarkFill(b, fillUint64) }
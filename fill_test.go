@@ -0,0 +1,119 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package randstat_test
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/splitmix64"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sources for testing the Fill functions, both with and without fast paths.
+var fillSources = []struct {
+	name      string
+	newSource func() rand.Source64
+}{
+	{"std", func() rand.Source64 { return rand.NewSource(42).(rand.Source64) }},
+	{"splitmix64", func() rand.Source64 { s := splitmix64.Source(42); return &s }},
+	{"xoshiro256", func() rand.Source64 { return xoshiro256.New(42) }},
+}
+
+// Lengths that are shorter than, equal to and longer than the internal chunks.
+var fillLengths = []int{0, 1, 7, 64, 65, 1000}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range fillSources {
+		for _, n := range fillLengths {
+			for _, extra := range []int{0, 3} {
+				r, ref := src.newSource(), src.newSource()
+
+				p := make([]byte, 8*n+extra)
+				m, err := randstat.Read(r, p)
+				assert.Equal(t, len(p), m)
+				assert.Nil(t, err)
+
+				expect := make([]byte, len(p)+8)
+				for i := 0; i < len(p); i += 8 {
+					binary.LittleEndian.PutUint64(expect[i:], ref.Uint64())
+				}
+				assert.Equal(t, expect[:len(p)], p, "%s, %d bytes", src.name, len(p))
+				assert.Equal(t, ref.Uint64(), r.Uint64())
+			}
+		}
+	}
+}
+
+func TestFillFloat64(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range fillSources {
+		for _, n := range fillLengths {
+			r, ref := src.newSource(), src.newSource()
+
+			dst := make([]float64, n)
+			randstat.FillFloat64(r, dst)
+			for i, x := range dst {
+				require.Equal(t, randstat.Float64(ref), x, "%s, index %d", src.name, i)
+			}
+			assert.Equal(t, ref.Uint64(), r.Uint64())
+		}
+	}
+}
+
+func TestFillIntn(t *testing.T) {
+	t.Parallel()
+
+	// maxint/3*2 gets rejected a quarter of the time.
+	for _, max := range []int{1, 10, int(^uint(0)>>1) / 3 * 2} {
+		for _, src := range fillSources {
+			for _, n := range fillLengths {
+				r, ref := src.newSource(), src.newSource()
+
+				dst := make([]int, n)
+				randstat.FillIntn(r, dst, max)
+				for i, x := range dst {
+					require.Equal(t, randstat.Intn(ref, max), x, "%s, index %d", src.name, i)
+				}
+				assert.Equal(t, ref.Uint64(), r.Uint64())
+			}
+		}
+	}
+
+	assert.Panics(t, func() { randstat.FillIntn(xoshiro256.New(1), nil, 0) })
+}
+
+func TestFillUint64(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range fillSources {
+		for _, n := range fillLengths {
+			r, ref := src.newSource(), src.newSource()
+
+			dst := make([]uint64, n)
+			randstat.FillUint64(r, dst)
+			for i, x := range dst {
+				require.Equal(t, ref.Uint64(), x, "%s, index %d", src.name, i)
+			}
+			assert.Equal(t, ref.Uint64(), r.Uint64())
+		}
+	}
+}
@@ -12,7 +12,11 @@
 
 package randstat
 
-import "math/rand"
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+)
 
 const machineEpsilon = 0x1p-53
 
@@ -26,3 +30,68 @@ func Float64(r rand.Source) float64 {
 	// adapted to non-negative int63 values.
 	return float64(r.Int63()>>10) * machineEpsilon
 }
+
+// Float64Open returns a pseudo-random number in the open interval (0,1),
+// given a uniformly random integer. Its result is a multiple of 2^-52,
+// offset by 2^-53. It consumes exactly one random integer from r.
+func Float64Open(r rand.Source) float64 {
+	return (float64(r.Int63()>>11) + .5) * 0x1p-52
+}
+
+// Float64Closed returns a pseudo-random number in the closed interval [0,1],
+// given a uniformly random integer. Its result is one of 2^53 equally
+// spaced values, k/(2^53-1) for an integer k, rounded to the nearest float64.
+// It consumes exactly one random integer from r.
+func Float64Closed(r rand.Source) float64 {
+	return float64(r.Int63()>>10) / (1<<53 - 1)
+}
+
+// Float32 returns a pseudo-random number in the interval [0,1),
+// given a uniformly random integer. Its result is a multiple of 2^-24.
+// It consumes exactly one random integer from r.
+func Float32(r rand.Source) float32 {
+	return float32(r.Int63()>>39) * 0x1p-24
+}
+
+// Float64Dense returns a pseudo-random number in the interval [0,1) that
+// can be any float64 in that interval, not just a multiple of 2^-53.
+// Its result is a uniform real number in [0,1), rounded down to a float64,
+// so values close to zero are returned with their correct, small
+// probabilities and zero is returned with probability 2^-1074.
+//
+// Float64Dense uses the method of Downey (2007), Generating Pseudo-random
+// Floating-Point Values: it draws a geometrically distributed exponent
+// and a uniform mantissa. It consumes one random integer from r, except
+// with probability 2^-12, when it needs more.
+func Float64Dense(r rand.Source64) float64 {
+	const (
+		mantBits = 52
+		minExp   = 1022 // Binades below 2^-minExp are subnormal.
+	)
+
+	u := r.Uint64()
+	mant := u & (1<<mantBits - 1)
+
+	// The number of leading zeros in a random bit string determines the
+	// binade: x is in [2^-(k+1), 2^-k) with probability 2^-(k+1).
+	var k int
+	if top := u >> mantBits; top != 0 {
+		k = bits.LeadingZeros64(top) - mantBits
+	} else {
+		k = 64 - mantBits
+		for k < minExp {
+			v := r.Uint64()
+			k += bits.LeadingZeros64(v)
+			if v != 0 {
+				break
+			}
+		}
+		if k > minExp {
+			k = minExp
+		}
+	}
+
+	// The biased exponent of [2^-(k+1), 2^-k) is 1022-k;
+	// exponent zero means a subnormal number.
+	return math.Float64frombits(uint64(minExp-k)<<mantBits | mant)
+}
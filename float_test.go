@@ -20,8 +20,11 @@ import (
 
 	"github.com/greatroar/randstat"
 	"github.com/greatroar/randstat/internal/source"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFloat64(t *testing.T) {
@@ -46,6 +49,75 @@ func TestFloat64(t *testing.T) {
 	}
 }
 
+func TestFloat64Open(t *testing.T) {
+	t.Parallel()
+
+	x := randstat.Float64Open(source.Constant(0))
+	assert.Equal(t, 0x1p-53, x)
+	x = randstat.Float64Open(source.Constant(1<<63 - 1))
+	assert.Equal(t, 1-0x1p-53, x)
+
+	r := xoshiro256.New(uint64(time.Now().UnixNano()))
+	for i := 0; i < 10000; i++ {
+		x := randstat.Float64Open(r)
+		assert.True(t, x > 0 && x < 1)
+	}
+}
+
+func TestFloat64Closed(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0., randstat.Float64Closed(source.Constant(0)))
+	assert.Equal(t, 1., randstat.Float64Closed(source.Constant(1<<63-1)))
+
+	r := xoshiro256.New(uint64(time.Now().UnixNano()))
+	for i := 0; i < 10000; i++ {
+		x := randstat.Float64Closed(r)
+		assert.True(t, x >= 0 && x <= 1)
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, float32(0), randstat.Float32(source.Constant(0)))
+	assert.Equal(t, float32(1-0x1p-24), randstat.Float32(source.Constant(1<<63-1)))
+
+	r := xoshiro256.New(uint64(time.Now().UnixNano()))
+	for i := 0; i < 10000; i++ {
+		x := randstat.Float32(r)
+		assert.True(t, x >= 0 && x < 1)
+	}
+}
+
+func TestFloat64Dense(t *testing.T) {
+	t.Parallel()
+
+	// All-zero bits take the slow path down to the subnormals.
+	assert.Equal(t, 0., randstat.Float64Dense(source.Constant(0)))
+	// Twelve zeros, then 63 more from a second value.
+	assert.Equal(t, 0x1p-76*(1+0x1p-52), randstat.Float64Dense(source.Constant(1)))
+	assert.Equal(t, 1-0x1p-53, randstat.Float64Dense(source.Constant(-1)))
+	// The top twelve bits hold the exponent, the rest the mantissa.
+	assert.Equal(t, .5, randstat.Float64Dense(source.Constant(-1<<63)))
+	assert.Equal(t, 0x1p-12, randstat.Float64Dense(source.Constant(1<<52)))
+
+	r := xoshiro256.New(1)
+	x := make([]float64, 100000)
+	fine := 0
+	for i := range x {
+		x[i] = randstat.Float64Dense(r)
+		require.True(t, x[i] >= 0 && x[i] < 1)
+		if x[i] < .25 && x[i] != math.Ldexp(math.Floor(math.Ldexp(x[i], 53)), -53) {
+			fine++
+		}
+	}
+	stattest.KS(t, x, func(x float64) float64 { return x })
+
+	// Nearly all values below 1/4 have bits beyond the 53rd.
+	assert.Greater(t, fine, len(x)/5)
+}
+
 func BenchmarkFloat64Std(b *testing.B) {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 
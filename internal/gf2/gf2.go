@@ -0,0 +1,75 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gf2 implements polynomial arithmetic over GF(2),
+// for jumping ahead in F2-linear random number generators.
+//
+// The state transition of such a generator is a linear map T on GF(2)^k.
+// Advancing by n steps means computing T^n. Since c(T) = 0 for
+// the characteristic polynomial c of T, T^n = q(T) for q = x^n mod c.
+// Evaluating q(T) on the state takes k steps of the generator.
+//
+// See Haramoto et al., Efficient Jump Ahead for F2-Linear Random Number
+// Generators, https://doi.org/10.1287/ijoc.1070.0251.
+package gf2
+
+import "math/big"
+
+// A Charpoly is a monic polynomial of degree 64*len(c), minus its leading
+// term. Bit i of c[i/64] is the coefficient of x^i.
+type Charpoly []uint64
+
+// XPow returns x^n mod c, in the same format as c.
+// The coefficients of the result are those of the "jump" arrays
+// in the reference implementations of xorshift-style generators.
+func (c Charpoly) XPow(n *big.Int) []uint64 {
+	r := make([]uint64, len(c))
+	r[0] = 1
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		r = c.mul(r, r)
+		if n.Bit(i) != 0 {
+			c.mulx(r)
+		}
+	}
+	return r
+}
+
+// mul returns p*q mod c.
+func (c Charpoly) mul(p, q []uint64) []uint64 {
+	r := make([]uint64, len(c))
+	for i := len(q) - 1; i >= 0; i-- {
+		for b := 63; b >= 0; b-- {
+			c.mulx(r)
+			if q[i]&(1<<uint(b)) != 0 {
+				for j := range r {
+					r[j] ^= p[j]
+				}
+			}
+		}
+	}
+	return r
+}
+
+// mulx sets p to p*x mod c.
+func (c Charpoly) mulx(p []uint64) {
+	carry := p[len(p)-1] >> 63
+	for i := len(p) - 1; i > 0; i-- {
+		p[i] = p[i]<<1 | p[i-1]>>63
+	}
+	p[0] <<= 1
+
+	if carry != 0 {
+		for i := range p {
+			p[i] ^= c[i]
+		}
+	}
+}
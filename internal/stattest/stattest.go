@@ -0,0 +1,130 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stattest contains statistical tests for testing random variate
+// generators. All tests are performed at a low significance level,
+// to keep tests with fixed seeds robust to small changes.
+package stattest
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// KS performs a Kolmogorov-Smirnov test of the sample x against the
+// distribution function cdf, at significance level .001. It sorts x.
+func KS(t testing.TB, x []float64, cdf func(float64) float64) bool {
+	t.Helper()
+
+	sort.Float64s(x)
+	n := float64(len(x))
+
+	var d float64
+	for i, xi := range x {
+		p := cdf(xi)
+		d = math.Max(d, math.Max(p-float64(i)/n, float64(i+1)/n-p))
+	}
+	return assert.Less(t, d*math.Sqrt(n), 1.95, "Kolmogorov-Smirnov statistic")
+}
+
+// Moments checks that the sample x has approximately the given mean and
+// variance. The mean must be within five standard errors, the sample
+// variance within relative error epsilon.
+func Moments(t testing.TB, x []float64, mean, variance, epsilon float64) bool {
+	t.Helper()
+
+	var sum float64
+	for _, xi := range x {
+		sum += xi
+	}
+	n := float64(len(x))
+	m := sum / n
+
+	var ss float64
+	for _, xi := range x {
+		ss += (xi - m) * (xi - m)
+	}
+	v := ss / (n - 1)
+
+	okMean := assert.InDelta(t, mean, m, 5*math.Sqrt(variance/n), "mean")
+	okVar := assert.InEpsilon(t, variance, v, epsilon, "variance")
+	return okMean && okVar
+}
+
+// Discrete performs a chi-squared goodness-of-fit test of the sample x
+// against the probability mass function pmf of a distribution on the
+// non-negative integers, at significance level .001.
+//
+// Values are pooled into bins with an expected count of at least five.
+func Discrete(t testing.TB, x []int64, pmf func(k int64) float64) bool {
+	t.Helper()
+
+	counts := make(map[int64]int)
+	var maxk int64
+	for _, k := range x {
+		if k < 0 {
+			return assert.Fail(t, "negative value in sample", "%d", k)
+		}
+		counts[k]++
+		if k > maxk {
+			maxk = k
+		}
+	}
+	n := float64(len(x))
+
+	type bin struct{ obs, exp float64 }
+	var (
+		bins      []bin
+		cur       bin
+		cum, seen float64
+	)
+	for k := int64(0); k <= maxk; k++ {
+		p := pmf(k)
+		cum += p
+		seen += float64(counts[k])
+		cur.obs += float64(counts[k])
+		cur.exp += n * p
+		if cur.exp >= 5 {
+			bins = append(bins, cur)
+			cur = bin{}
+		}
+	}
+	cur.obs += n - seen
+	cur.exp += n * math.Max(0, 1-cum)
+	if len(bins) > 0 && cur.exp < 5 {
+		last := &bins[len(bins)-1]
+		last.obs += cur.obs
+		last.exp += cur.exp
+	} else {
+		bins = append(bins, cur)
+	}
+
+	if len(bins) < 2 {
+		// Degenerate distribution: all mass expected in one bin.
+		return assert.Equal(t, bins[0].exp, bins[0].obs, "observed count")
+	}
+
+	var chi2 float64
+	for _, b := range bins {
+		chi2 += (b.obs - b.exp) * (b.obs - b.exp) / b.exp
+	}
+
+	// Wilson-Hilferty approximation of the .999 quantile.
+	df := float64(len(bins) - 1)
+	h := 2 / (9 * df)
+	crit := df * math.Pow(1-h+3.09*math.Sqrt(h), 3)
+
+	return assert.Less(t, chi2, crit, "chi-squared statistic, %v degrees of freedom", df)
+}
@@ -40,10 +40,26 @@ func Intn(r rand.Source64, n int) int {
 //
 // n must be greater than 0. r must not be nil.
 func Int31n(r rand.Source, n int32) int32 {
+	return int32(uint32n(r, uint32(n)))
+}
+
+// Uint32n returns a uniformly random integer from the range [0,n).
+// It panics if n == 0. r must not be nil.
+//
+// For a math/rand/v2 Source, use Uint32N.
+func Uint32n(r rand.Source, n uint32) uint32 {
+	if n == 0 {
+		panic("randstat.Uint32n: n == 0")
+	}
+	return uint32n(r, n)
+}
+
+// uint32n returns a uniformly random integer from the range [0,u).
+// u must be greater than 0.
+func uint32n(r rand.Source, u uint32) uint32 {
 	// Algorithm (5) from https://arxiv.org/pdf/1805.10941.pdf.
 	// We could check the popcnt of n for powers of two, but that slows us
 	// down 5% for other numbers (on amd64).
-	u := uint32(n)
 
 	// 32-bit random number, as a uint64.
 	r32 := func() uint64 {
@@ -60,25 +76,138 @@ func Int31n(r rand.Source, n int32) int32 {
 		}
 	}
 
-	return int32(m >> 32)
+	return uint32(m >> 32)
 }
 
 // Int63n returns a uniformly random integer from the range [0,n).
 //
 // n must be greater than 0. r must not be nil.
 func Int63n(r rand.Source64, n int64) int64 {
-	// See comment in Int31n.
-	u := uint64(n)
+	return int64(uint64n(r, uint64(n)))
+}
+
+// Uint64n returns a uniformly random integer from the range [0,n).
+// Unlike Int63n, it accepts any n up to 2^64-1.
+// It panics if n == 0. r must not be nil.
+//
+// For a math/rand/v2 Source, use Uint64N.
+func Uint64n(r rand.Source64, n uint64) uint64 {
+	if n == 0 {
+		panic("randstat.Uint64n: n == 0")
+	}
+	return uint64n(r, n)
+}
+
+// IntRange returns a uniformly random integer from the range [lo,hi).
+// The range may span zero and contain up to 2^64-1 integers.
+// It panics if hi <= lo. r must not be nil.
+func IntRange(r rand.Source64, lo, hi int64) int64 {
+	if hi <= lo {
+		panic("randstat.IntRange: hi <= lo")
+	}
+	// The span hi-lo wraps around, but is correct as a uint64.
+	return lo + int64(uint64n(r, uint64(hi)-uint64(lo)))
+}
+
+// Uint128n returns a uniformly random 128-bit integer from the range [0,n),
+// where n = nhi<<64 | nlo, as its upper and lower halves.
+// It panics if n == 0. r must not be nil.
+//
+// Uint128n uses the same algorithm as Uint64n, extended to 128 bits.
+// It consumes two values from r, except when it has to reject
+// a sample, which happens with probability less than n/2^128.
+func Uint128n(r rand.Source64, nhi, nlo uint64) (hi, lo uint64) {
+	if nhi == 0 && nlo == 0 {
+		panic("randstat.Uint128n: n == 0")
+	}
+
+	// The product of a random 128-bit x and n is a 256-bit number.
+	// Its upper half is the result, unless its lower half is below
+	// 2^128 mod n.
+	mul := func() (w3, w2, w1, w0 uint64) {
+		x1, x0 := r.Uint64(), r.Uint64()
+
+		h00, l00 := bits.Mul64(x0, nlo)
+		h01, l01 := bits.Mul64(x0, nhi)
+		h10, l10 := bits.Mul64(x1, nlo)
+		h11, l11 := bits.Mul64(x1, nhi)
+
+		var c1, c2 uint64
+		w0 = l00
+		w1, c1 = bits.Add64(h00, l01, 0)
+		w1, c2 = bits.Add64(w1, l10, 0)
+		w2, c1 = bits.Add64(h01, h10, c1)
+		w3 = h11 + c1
+		w2, c1 = bits.Add64(w2, l11, c2)
+		w3 += c1
+		return
+	}
+
+	hi, lo, mhi, mlo := mul()
+	if mhi < nhi || mhi == nhi && mlo < nlo {
+		// Compute (2^128 - n) mod n.
+		neglo, borrow := bits.Sub64(0, nlo, 0)
+		neghi, _ := bits.Sub64(0, nhi, borrow)
+		thi, tlo := rem128(neghi, neglo, nhi, nlo)
+
+		for mhi < thi || mhi == thi && mlo < tlo {
+			hi, lo, mhi, mlo = mul()
+		}
+	}
+
+	return hi, lo
+}
+
+// rem128 returns u mod v for 128-bit integers u and v != 0,
+// using the algorithm of Warren, Hacker's Delight, section 9-5.
+func rem128(uhi, ulo, vhi, vlo uint64) (rhi, rlo uint64) {
+	if vhi == 0 {
+		return 0, bits.Rem64(uhi%vlo, ulo, vlo)
+	}
+
+	// Normalize v so that its top bit is set, then estimate the quotient
+	// from the top words. After decrementing, the estimate is either
+	// correct or one too small.
+	s := uint(bits.LeadingZeros64(vhi))
+	vtop := vhi<<s | vlo>>(64-s)
+	q, _ := bits.Div64(uhi>>1, uhi<<63|ulo>>1, vtop)
+	q >>= 63 - s
+	if q != 0 {
+		q--
+	}
+
+	// r = u - q*v, then correct the quotient.
+	phi, plo := bits.Mul64(q, vlo)
+	phi += q * vhi
+	rlo, borrow := bits.Sub64(ulo, plo, 0)
+	rhi, _ = bits.Sub64(uhi, phi, borrow)
+	if rhi > vhi || rhi == vhi && rlo >= vlo {
+		rlo, borrow = bits.Sub64(rlo, vlo, 0)
+		rhi, _ = bits.Sub64(rhi, vhi, borrow)
+	}
+	return rhi, rlo
+}
+
+// uint64Source is the part of rand.Source64 and math/rand/v2.Source
+// that uint64n needs, so that it can serve both.
+type uint64Source interface {
+	Uint64() uint64
+}
 
-	hi, lo := bits.Mul64(uint64(r.Uint64()), uint64(n))
+// uint64n returns a uniformly random integer from the range [0,u).
+// u must be greater than 0.
+func uint64n(r uint64Source, u uint64) uint64 {
+	// Algorithm (5) from https://arxiv.org/pdf/1805.10941.pdf;
+	// see also the comment in uint32n.
+	hi, lo := bits.Mul64(r.Uint64(), u)
 	if lo < u {
 		thresh := (-u) % u
 		for lo < thresh {
-			hi, lo = bits.Mul64(uint64(r.Uint64()), u)
+			hi, lo = bits.Mul64(r.Uint64(), u)
 		}
 	}
 
-	return int64(hi)
+	return hi
 }
 
 // Shuffle generates a random permutation.
@@ -13,10 +13,13 @@
 package randstat_test
 
 import (
+	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 
 	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/internal/source"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -84,6 +87,117 @@ func TestInt63n(t *testing.T) {
 	}
 }
 
+func TestUint32n(t *testing.T) {
+	t.Parallel()
+
+	r := rand.NewSource(0x6983662)
+
+	for _, max := range []uint32{1, 163, 1 << 20, 1<<31 + 1, 1<<32 - 1} {
+		for i := 0; i < 20000; i++ {
+			require.Less(t, randstat.Uint32n(r, max), max)
+		}
+	}
+	assert.Panics(t, func() { randstat.Uint32n(r, 0) })
+}
+
+func TestUint64n(t *testing.T) {
+	t.Parallel()
+
+	r := rand.NewSource(0x6983663).(rand.Source64)
+
+	for _, max := range []uint64{1, 2, 198687, 1 << 63, 1<<63 + 1, 1<<64 - 1} {
+		for i := 0; i < 20000; i++ {
+			require.Less(t, randstat.Uint64n(r, max), max)
+		}
+	}
+	assert.Panics(t, func() { randstat.Uint64n(r, 0) })
+}
+
+func TestIntRange(t *testing.T) {
+	t.Parallel()
+
+	r := rand.NewSource(0x6983664).(rand.Source64)
+
+	for _, c := range []struct{ lo, hi int64 }{
+		{0, 1}, {-1, 0}, {-10, 10}, {math.MinInt64, 0}, {-1, math.MaxInt64},
+		{math.MinInt64, math.MaxInt64}, {math.MaxInt64 - 1, math.MaxInt64},
+	} {
+		for i := 0; i < 20000; i++ {
+			x := randstat.IntRange(r, c.lo, c.hi)
+			require.True(t, c.lo <= x && x < c.hi)
+		}
+	}
+
+	// Spans of more than 2^63 produce both signs.
+	var neg, pos int
+	for i := 0; i < 1000; i++ {
+		if randstat.IntRange(r, math.MinInt64, math.MaxInt64) < 0 {
+			neg++
+		} else {
+			pos++
+		}
+	}
+	assert.InEpsilon(t, neg, pos, .2)
+
+	assert.Panics(t, func() { randstat.IntRange(r, 0, 0) })
+	assert.Panics(t, func() { randstat.IntRange(r, 1, -1) })
+}
+
+func TestUint128n(t *testing.T) {
+	t.Parallel()
+
+	two64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	toBig := func(hi, lo uint64) *big.Int {
+		x := new(big.Int).SetUint64(hi)
+		x.Mul(x, two64)
+		return x.Add(x, new(big.Int).SetUint64(lo))
+	}
+
+	ns := [][2]uint64{
+		{0, 1}, {0, 3}, {0, 1<<64 - 1}, {1, 0}, {1, 1}, {3 << 62, 0},
+		{1<<63 + 5, 17}, {1<<64 - 1, 1<<64 - 1},
+	}
+
+	// With a constant source, x = c<<64 | c and the result is
+	// floor(x*n / 2^128), if it is not rejected.
+	for _, n := range ns {
+		nbig := toBig(n[0], n[1])
+		for _, c := range []int64{0, 1, 0x5deece66d, math.MaxInt64, -2, -1} {
+			x := toBig(uint64(c), uint64(c))
+			m := new(big.Int).Mul(x, nbig)
+			low := new(big.Int).Mod(m, new(big.Int).Lsh(big.NewInt(1), 128))
+			thresh := new(big.Int).Mod(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), nbig), nbig)
+			if low.Cmp(thresh) < 0 {
+				continue
+			}
+
+			hi, lo := randstat.Uint128n(source.Constant(c), n[0], n[1])
+			assert.Equal(t, m.Rsh(m, 128).String(), toBig(hi, lo).String())
+		}
+	}
+
+	r := rand.NewSource(0x6983665).(rand.Source64)
+	for _, n := range ns {
+		nbig := toBig(n[0], n[1])
+		for i := 0; i < 2000; i++ {
+			hi, lo := randstat.Uint128n(r, n[0], n[1])
+			require.True(t, toBig(hi, lo).Cmp(nbig) < 0)
+		}
+	}
+
+	// Rejects a quarter of the products.
+	freq := make([]int, 3)
+	for i := 0; i < 30000; i++ {
+		hi, _ := randstat.Uint128n(r, 3<<62, 0)
+		freq[hi>>62]++
+	}
+	for _, f := range freq {
+		assert.InEpsilon(t, 10000, f, .05)
+	}
+
+	assert.Panics(t, func() { randstat.Uint128n(r, 0, 0) })
+}
+
 // Very quick statistical check.
 func TestInt31nSmallStats(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,117 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcg
+
+import "math/big"
+
+// The "cheap multiplier" of PCG64DXSM. It serves both as the LCG multiplier
+// and in the output function.
+const cheapMult = 0xda942042e4dd58b5
+
+// A DXSM is a PCG64DXSM random number generator, a 128-bit LCG with a 64-bit
+// multiplier and the DXSM (double xorshift multiply) output function.
+//
+// The zero DXSM is not usable; it must be seeded first.
+type DXSM struct {
+	state, inc uint128
+}
+
+// NewDXSM returns a DXSM initialized with the given seed.
+// It is equivalent to allocating a DXSM and calling Seed on it.
+func NewDXSM(seed uint64) *DXSM {
+	s := &DXSM{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// NewDXSMStream returns a DXSM initialized with the given initstate
+// and initseq. It is equivalent to allocating a DXSM and calling
+// SeedStream on it.
+func NewDXSMStream(initstate, initseq [2]uint64) *DXSM {
+	s := &DXSM{}
+	s.SeedStream(initstate, initseq)
+	return s
+}
+
+// Advance advances the DXSM by delta positions.
+func (s *DXSM) Advance(delta uint64) {
+	s.state = advanceLCG(s.state, uint128{0, delta}, uint128{0, cheapMult}, s.inc)
+}
+
+// AdvanceBig advances the DXSM by delta positions, modulo 2^128.
+// A negative delta moves the DXSM backwards.
+func (s *DXSM) AdvanceBig(delta *big.Int) {
+	s.state = advanceLCG(s.state, bigToUint128(delta), uint128{0, cheapMult}, s.inc)
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *DXSM) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state.
+// The seed value may be any 64-bit integer.
+//
+// It uses a SplitMix64 generator to turn seed into an initstate and initseq
+// for SeedStream.
+func (s *DXSM) Seed(seed int64) {
+	initstate, initseq := splitmixSeed(seed)
+	s.SeedStream(initstate, initseq)
+}
+
+// SeedStream initializes the generator using the seeding procedure of
+// the PCG reference implementation. The arguments are 128-bit integers,
+// stored as {high, low} 64-bit halves.
+//
+// The initseq selects one of 2^127 distinct streams. NumPy's PCG64DXSM passes
+// the first two and last two words of its SeedSequence's
+// generate_state(4, np.uint64) as initstate and initseq.
+func (s *DXSM) SeedStream(initstate, initseq [2]uint64) {
+	s.inc = uint128{initseq[0]<<1 | initseq[1]>>63, initseq[1]<<1 | 1}
+	s.state = uint128{}
+	s.step()
+	s.state = s.state.add(uint128{initstate[0], initstate[1]})
+	s.step()
+}
+
+func (s *DXSM) step() { s.state = s.state.mul(uint128{0, cheapMult}).add(s.inc) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *DXSM) Uint64() uint64 {
+	// The output function is applied to the state before the LCG step.
+	hi, lo := s.state.hi, s.state.lo|1
+	hi ^= hi >> 32
+	hi *= cheapMult
+	hi ^= hi >> 48
+	hi *= lo
+
+	s.step()
+	return hi
+}
+
+const headerDXSM = "pcg64 dxsm\x00\x00\x00\x00\x00\x00"
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format is the same as that of Source.MarshalBinary,
+// but with a different header.
+//
+// The returned error is always nil.
+func (s *DXSM) MarshalBinary() (data []byte, err error) {
+	return marshal(headerDXSM, s.state, s.inc), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *DXSM) UnmarshalBinary(data []byte) (err error) {
+	s.state, s.inc, err = unmarshal(headerDXSM, data)
+	return err
+}
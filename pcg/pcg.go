@@ -0,0 +1,171 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Derived from the PCG reference implementation, https://www.pcg-random.org/,
+// and its adaptation in NumPy, https://numpy.org/:
+//
+// Copyright 2014-2019 Melissa O'Neill <oneill@pcg-random.org>,
+// and the PCG Project contributors.
+//
+// Licensed under the Apache License, Version 2.0 or the MIT license.
+
+// Package pcg implements 128-bit state PCG random number generators.
+//
+// Source is the classic PCG64 (XSL-RR) generator and DXSM is the PCG64DXSM
+// variant. Both produce the same streams as NumPy's PCG64 and PCG64DXSM
+// bit generators, and the PCG reference implementation, when seeded with
+// the same initstate and initseq.
+package pcg
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/greatroar/randstat/splitmix64"
+)
+
+// Default multiplier for 128-bit LCGs.
+var mult128 = uint128{2549297995355413924, 4865540595714422341}
+
+// A Source is a PCG64 random number generator, a 128-bit LCG with
+// the XSL-RR output function.
+//
+// The zero Source is not usable; it must be seeded first.
+type Source struct {
+	state, inc uint128
+}
+
+// New returns a Source initialized with the given seed.
+// It is equivalent to allocating a Source and calling Seed on it.
+func New(seed uint64) *Source {
+	s := &Source{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// NewStream returns a Source initialized with the given initstate and initseq.
+// It is equivalent to allocating a Source and calling SeedStream on it.
+func NewStream(initstate, initseq [2]uint64) *Source {
+	s := &Source{}
+	s.SeedStream(initstate, initseq)
+	return s
+}
+
+// Advance advances the Source by delta positions.
+func (s *Source) Advance(delta uint64) {
+	s.state = advanceLCG(s.state, uint128{0, delta}, mult128, s.inc)
+}
+
+// AdvanceBig advances the Source by delta positions, modulo 2^128.
+// A negative delta moves the Source backwards.
+func (s *Source) AdvanceBig(delta *big.Int) {
+	s.state = advanceLCG(s.state, bigToUint128(delta), mult128, s.inc)
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state.
+// The seed value may be any 64-bit integer.
+//
+// It uses a SplitMix64 generator to turn seed into an initstate and initseq
+// for SeedStream.
+func (s *Source) Seed(seed int64) {
+	initstate, initseq := splitmixSeed(seed)
+	s.SeedStream(initstate, initseq)
+}
+
+// SeedStream initializes the generator using the seeding procedure of
+// the PCG reference implementation. The arguments are 128-bit integers,
+// stored as {high, low} 64-bit halves.
+//
+// The initseq selects one of 2^127 distinct streams. NumPy's PCG64 passes
+// the first two and last two words of its SeedSequence's
+// generate_state(4, np.uint64) as initstate and initseq.
+func (s *Source) SeedStream(initstate, initseq [2]uint64) {
+	s.inc = uint128{initseq[0]<<1 | initseq[1]>>63, initseq[1]<<1 | 1}
+	s.state = uint128{}
+	s.step()
+	s.state = s.state.add(uint128{initstate[0], initstate[1]})
+	s.step()
+}
+
+func (s *Source) step() { s.state = s.state.mul(mult128).add(s.inc) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Source) Uint64() uint64 {
+	s.step()
+	return bits.RotateLeft64(s.state.hi^s.state.lo, -int(s.state.hi>>58))
+}
+
+const (
+	header      = "pcg64 xsl-rr\x00\x00\x00\x00"
+	marshalSize = len(header) + 4*8
+)
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format starts with a 16-byte header, followed by the 128-bit state
+// and the 128-bit increment, each as two 64-bit integers in little-endian
+// format, high half first.
+//
+// The returned error is always nil.
+func (s *Source) MarshalBinary() (data []byte, err error) {
+	return marshal(header, s.state, s.inc), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *Source) UnmarshalBinary(data []byte) (err error) {
+	s.state, s.inc, err = unmarshal(header, data)
+	return err
+}
+
+func marshal(header string, state, inc uint128) []byte {
+	data := make([]byte, marshalSize)
+	copy(data, header)
+	for i, x := range [...]uint64{state.hi, state.lo, inc.hi, inc.lo} {
+		binary.LittleEndian.PutUint64(data[len(header)+8*i:], x)
+	}
+	return data
+}
+
+func unmarshal(header string, data []byte) (state, inc uint128, err error) {
+	switch {
+	case len(data) != marshalSize:
+		err = errors.New("unmarshal pcg: incorrect data length")
+		return
+	case string(data[:len(header)]) != header:
+		err = errors.New("unmarshal pcg: incorrect header")
+		return
+	}
+
+	data = data[len(header):]
+	state.hi = binary.LittleEndian.Uint64(data)
+	state.lo = binary.LittleEndian.Uint64(data[8:])
+	inc.hi = binary.LittleEndian.Uint64(data[16:])
+	inc.lo = binary.LittleEndian.Uint64(data[24:])
+
+	if inc.lo&1 == 0 {
+		err = errors.New("unmarshal pcg: even increment")
+	}
+	return
+}
+
+func splitmixSeed(seed int64) (initstate, initseq [2]uint64) {
+	sm := splitmix64.Source(seed)
+	initstate = [2]uint64{sm.Uint64(), sm.Uint64()}
+	initseq = [2]uint64{sm.Uint64(), sm.Uint64()}
+	return
+}
@@ -0,0 +1,311 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcg_test
+
+import (
+	"encoding"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/pcg"
+	"github.com/greatroar/randstat/seedseq"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*pcg.Source)(nil)
+	_ encoding.BinaryUnmarshaler = (*pcg.Source)(nil)
+	_ rand.Source64              = (*pcg.Source)(nil)
+
+	_ encoding.BinaryMarshaler   = (*pcg.DXSM)(nil)
+	_ encoding.BinaryUnmarshaler = (*pcg.DXSM)(nil)
+	_ rand.Source64              = (*pcg.DXSM)(nil)
+)
+
+// Common interface of Source and DXSM, for testing.
+type source interface {
+	rand.Source64
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	Advance(uint64)
+	AdvanceBig(*big.Int)
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	// Values from the PCG reference implementation's test suite,
+	// with initstate 42 and initseq 54. NumPy's PCG64 produces the same
+	// values when seeded with these arguments.
+	s := pcg.NewStream([2]uint64{0, 42}, [2]uint64{0, 54})
+	for i, v := range [...]uint64{
+		0x86b1da1d72062b68, 0x1304aa46c9853d39, 0xa3670e9e0dd50358,
+		0xf9090e529a7dae00, 0xc85b9fd837996f2c, 0x606121f8e3919196,
+	} {
+		assert.Equal(t, v, s.Uint64(), "value %d", i)
+	}
+
+	for _, c := range []struct {
+		seed uint64
+		r    [15]uint64
+	}{
+		// Regression values for Seed, which bootstraps initstate
+		// and initseq from SplitMix64.
+		{seed: 0x00000000,
+			r: [...]uint64{
+				0xcb40115cbf8d9cb4, 0xc1c3da57af3c3e9, 0xddabdc2025f5a5d4,
+				0x8d09d8ba10bd7974, 0xe7c15487f52f392d, 0xe65c5b2cb7a84a7,
+				0x1255c2fb6c61ed6c, 0xba91aea7434d8753, 0x7596919760ccd09d,
+				0xfdc8a0d29ff44d, 0x19fca5ace0dd00f3, 0xc18c8f06f9b50a0b,
+				0x523acbe3c6f5891e, 0x7c9cb561786d5e34, 0x2b49d64cc0b6f1f0,
+			}},
+
+		{seed: 0x00000001,
+			r: [...]uint64{
+				0x53a7b5f9d76612d6, 0x637f433e6f898642, 0x2765c86d1bf99840,
+				0x781ed338eb9c21b0, 0xc9459606b3b4d902, 0x482b53fccdfda46b,
+				0x8108c852e67bdd47, 0xc7e9d7cdefbd0e56, 0xb5a50951c30b0bbb,
+				0x4b5030cda82e7a26, 0x121fadcf35e05ac6, 0x3a5c5ce361a3696b,
+				0xb2571dc2245f954c, 0xbd09f4adc8d46ba1, 0xab07264cf92f05a2,
+			}},
+
+		{seed: 0x123456789,
+			r: [...]uint64{
+				0x548ac163da04bcc, 0xea94c53f33a447ae, 0xbcd62dcb1bb15f11,
+				0x5f1bc9d5247f926c, 0x5dbe42cbc513b0e8, 0xf6910ae52c2b4af6,
+				0xda94927fed07d30d, 0xc8b91fcc42615088, 0xcbc069221caef7f7,
+				0xa0011cba1b4f34c8, 0x2d2df8c03cbb63af, 0xf33f8e69c1edfb35,
+				0x723397fe7c9f1273, 0x5848d52d7ea7da66, 0x368ca0cb24655ccd,
+			}},
+
+		{seed: 0x05f5e0ff,
+			r: [...]uint64{
+				0x21f9b1f0fb08247c, 0x46033777168f303f, 0x721e126bf3d1faa2,
+				0x9b538dfeaf7de4b6, 0xa975ed3f5653e8a9, 0x417f968b09b7c603,
+				0x9e32a8343faa8b0f, 0xefee8fe9cd67a0ea, 0x281db72e410a941d,
+				0x75eb7dd56c396288, 0x2b77230765b1687a, 0x9052c3eb11fdc808,
+				0x3c23c8a49bda9809, 0xf96895db4630863f, 0x970583bf8e5b2ab0,
+			}},
+	} {
+		s := pcg.New(c.seed)
+		for i, v := range c.r {
+			assert.Equal(t, v, s.Uint64(), "seed = 0x%08x, value %d", c.seed, i)
+		}
+	}
+}
+
+func TestDXSM(t *testing.T) {
+	t.Parallel()
+
+	// Values from the PCG reference implementation's test suite,
+	// with initstate 42 and initseq 54. NumPy's PCG64DXSM produces the same
+	// values when seeded with these arguments.
+	s := pcg.NewDXSMStream([2]uint64{0, 42}, [2]uint64{0, 54})
+	for i, v := range [...]uint64{
+		0xf0847c9518bddb90, 0x8e7d5f5514ba8aaa, 0x86fbd36f8028f6fd,
+		0x8d14b6edbe9f740a, 0xa85b2896c7cad55d, 0x8ca3894a1d9227bb,
+	} {
+		assert.Equal(t, v, s.Uint64(), "value %d", i)
+	}
+
+	for _, c := range []struct {
+		seed uint64
+		r    [15]uint64
+	}{
+		// Regression values for Seed, which bootstraps initstate
+		// and initseq from SplitMix64.
+		{seed: 0x00000000,
+			r: [...]uint64{
+				0xa0b6259b0b187d87, 0xa63db0caf23586e4, 0x96152efdfd76696a,
+				0xa2dd7e4eebd8a22d, 0x3a252d9776004a9, 0xe93d08de0e1d21d5,
+				0xa4e7a1c28dd57cb4, 0x90b6ce10a5efc762, 0x46742793f80a9a98,
+				0x45d590038839d364, 0x72660ad2ac0191b7, 0x54498eaf0b81ada1,
+				0x355c127a790e12ec, 0x52d6ec25465678eb, 0x706a867c4b3d9a49,
+			}},
+
+		{seed: 0x00000001,
+			r: [...]uint64{
+				0x9c0cdf2cbee849ea, 0x89fdfcf8c26edbd0, 0x3fe5e6d3c39ae385,
+				0xbe2e07885ad7485f, 0xbb0099f30e2c554, 0x4526b929f3ee39f4,
+				0xbef4fe09ee440953, 0x5f0d6843f09cea79, 0xcaf4839f62c38a5e,
+				0x11c06e2e22561df9, 0xefb221f3cae054a, 0x6bf8813e33b70734,
+				0x83ffd63cd430024c, 0x98286608d29643c, 0xe50fdff8031578e8,
+			}},
+
+		{seed: 0x123456789,
+			r: [...]uint64{
+				0x77f9b1fa71b4bc95, 0x1a98125a64a77762, 0xc4f6fdff66b1e23a,
+				0xc428778309e90e54, 0x7a346d2978ba6522, 0xb58857cf9bdc294d,
+				0xbc7cc84d0a7adfef, 0x1e4c96bf2215487, 0x46315a3afad9c50c,
+				0xf9c30f7177cb095c, 0xeb7c4a27740808c2, 0xc1798b091a6eeebe,
+				0x61653a48e205b5ab, 0xcd70c36a6e5f287a, 0x1eb9c200458cfe75,
+			}},
+
+		{seed: 0x05f5e0ff,
+			r: [...]uint64{
+				0x2a68eb2ed5be38d6, 0x1d1781032eee34a0, 0x83dae57d983b8894,
+				0x736c58fe507f6517, 0x9460df20d5e80d7d, 0xba3c8d40c513dff8,
+				0x46e031a7f5606eb5, 0xd8bd1a458c67ea8, 0x5666fa07c3474dfa,
+				0x4d41801c027f497, 0x3da4b454ff4c3cff, 0x43ffaefeaf6a2701,
+				0xa828a3172b9c13a1, 0xda5d1506d0ea17c2, 0xac2d23069673d443,
+			}},
+	} {
+		s := pcg.NewDXSM(c.seed)
+		for i, v := range c.r {
+			assert.Equal(t, v, s.Uint64(), "seed = 0x%08x, value %d", c.seed, i)
+		}
+	}
+}
+
+// Streams for the seeds of NumPy's pcg64 and pcg64dxsm test sets
+// (numpy/random/tests/data/pcg64*-testset-*.csv), which NumPy
+// expands to a state through a SeedSequence.
+func TestNumPy(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		seed        uint64
+		pcg64, dxsm [15]uint64
+	}{
+		{seed: 0xdeadbeaf,
+			pcg64: [...]uint64{
+				0x60d24054e17a0698, 0xd5e79d89856e4f12, 0xd254972fe64bd782,
+				0xf1e3072a53c72571, 0xd7c1d7393d4115c9, 0x77b75928b763e1e2,
+				0xee6dee05190f7909, 0x15f7b1c51d7fa319, 0x27e44105f26ac2d7,
+				0xcc0d88b29e5b415, 0xe07b1a90c685e361, 0xd2e430240de95e38,
+				0x3260bca9a24ca9da, 0x9b3cf2e92385adb7, 0x30b5514548271976,
+			},
+			dxsm: [...]uint64{
+				0x353db1139412b43b, 0x207a578fed91f8fa, 0xe716b05be6eb4aac,
+				0x6d6d252bb51470c, 0x534121e5e1fc9238, 0x6c9415a7cbb91e75,
+				0x96c691e0d5603f34, 0xd68df5cb40716602, 0x6e492c8f8634b062,
+				0x8fc18e05b545c6f5, 0x53381282dd6eb9b0, 0x167db7ec121c41ab,
+				0x39af5a787a9e95d3, 0x9a9fb8f53e51e386, 0x74f48df45a2c3f35,
+			}},
+
+		{seed: 0,
+			pcg64: [...]uint64{
+				0xa30febcfd9c2825f, 0x4510bdf882d9d721, 0xa7d3da94ecde8b8,
+				0x43b27b61342f01d, 0xd0327a782cde513b, 0xe9aa5979a6401c4e,
+				0x9b4c7b7180edb27f, 0xbac0495ff8829a45, 0x8b2b01e7a1dc7fbf,
+				0xef60e8078f56bfed, 0xd0dbc74d4700374c, 0xb37868abbe90b0,
+				0xdb7ed8bf64e6f5f0, 0x89910738de7951f, 0xbacab307c3cfd379,
+			},
+			dxsm: [...]uint64{
+				0x2f0e89987d87f95, 0xfd78ede278533ed7, 0x112facdd92bc4b41,
+				0x6a3158afcc56e201, 0x1dde89778354675d, 0x679648405a219166,
+				0xb62d2d9193b1f23b, 0x825d95800942df53, 0xa6e503672305e3f4,
+				0xb5c33375d865313, 0x2aefc25821ebc4cd, 0x6d4696e1319e7f35,
+				0x5ca91337fa510ff, 0xed09dd119a05942b, 0xeca85b4a42b1d915,
+			}},
+	} {
+		s := seedseq.New(c.seed).PCG64()
+		for i, v := range c.pcg64 {
+			assert.Equal(t, v, s.Uint64(), "PCG64, seed = 0x%08x, value %d", c.seed, i)
+		}
+		d := seedseq.New(c.seed).PCG64DXSM()
+		for i, v := range c.dxsm {
+			assert.Equal(t, v, d.Uint64(), "PCG64DXSM, seed = 0x%08x, value %d", c.seed, i)
+		}
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	t.Parallel()
+
+	for _, newSource := range []func(uint64) source{
+		func(seed uint64) source { return pcg.New(seed) },
+		func(seed uint64) source { return pcg.NewDXSM(seed) },
+	} {
+		for _, delta := range []uint64{0, 1, 2, 17, 1000, 12345} {
+			a, b := newSource(delta), newSource(delta)
+			for i := uint64(0); i < delta; i++ {
+				a.Uint64()
+			}
+			b.Advance(delta)
+			assert.Equal(t, a.Uint64(), b.Uint64())
+
+			// Going back delta+1 steps should return the initial state.
+			b.AdvanceBig(new(big.Int).SetInt64(-1 - int64(delta)))
+			assert.Equal(t, newSource(delta).Uint64(), b.Uint64())
+		}
+
+		// The period is 2^128.
+		a, b := newSource(4), newSource(4)
+		b.AdvanceBig(new(big.Int).Lsh(big.NewInt(1), 128))
+		assert.Equal(t, a.Uint64(), b.Uint64())
+
+		// Advancing by 2^64 in two steps.
+		b.AdvanceBig(new(big.Int).Lsh(big.NewInt(1), 64))
+		a.Advance(1<<64 - 1)
+		a.Advance(1)
+		assert.Equal(t, a.Uint64(), b.Uint64())
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	for _, newSource := range []func(uint64) source{
+		func(seed uint64) source { return pcg.New(seed) },
+		func(seed uint64) source { return pcg.NewDXSM(seed) },
+	} {
+		for i := 0; i < 100; i++ {
+			s := newSource(uint64(i))
+
+			marshaled, err := s.MarshalBinary()
+			assert.Nil(t, err)
+
+			expect := s.Uint64()
+			err = s.UnmarshalBinary(marshaled)
+			assert.Nil(t, err)
+
+			actual := s.Uint64()
+			assert.Equal(t, expect, actual)
+		}
+
+		s := newSource(0)
+		err := s.UnmarshalBinary(nil)
+		assert.NotNil(t, err)
+		err = s.UnmarshalBinary(make([]byte, 6*8))
+		assert.NotNil(t, err)
+	}
+
+	// Headers differ between Source and DXSM.
+	marshaled, _ := pcg.New(1).MarshalBinary()
+	assert.NotNil(t, new(pcg.DXSM).UnmarshalBinary(marshaled))
+
+	// Increments must be odd.
+	marshaled[len(marshaled)-8] &^= 1
+	assert.NotNil(t, new(pcg.Source).UnmarshalBinary(marshaled))
+}
+
+func BenchmarkSourceUint64(b *testing.B) {
+	r := pcg.New(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkDXSMUint64(b *testing.B) {
+	r := pcg.NewDXSM(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcg
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Unsigned 128-bit integer, with arithmetic modulo 2^128.
+type uint128 struct{ hi, lo uint64 }
+
+func (x uint128) add(y uint128) uint128 {
+	lo, c := bits.Add64(x.lo, y.lo, 0)
+	hi, _ := bits.Add64(x.hi, y.hi, c)
+	return uint128{hi, lo}
+}
+
+func (x uint128) mul(y uint128) uint128 {
+	hi, lo := bits.Mul64(x.lo, y.lo)
+	hi += x.hi*y.lo + x.lo*y.hi
+	return uint128{hi, lo}
+}
+
+func (x uint128) isZero() bool { return x.hi|x.lo == 0 }
+
+// bigToUint128 reduces x modulo 2^128.
+func bigToUint128(x *big.Int) uint128 {
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	x = new(big.Int).Mod(x, mod)
+
+	lo := new(big.Int).And(x, new(big.Int).SetUint64(1<<64-1))
+	hi := x.Rsh(x, 64)
+	return uint128{hi.Uint64(), lo.Uint64()}
+}
+
+// advanceLCG returns the state of the LCG x -> mult*x + inc
+// after delta steps from state.
+//
+// This is Brown's algorithm, "Random Number Generation with Arbitrary Strides",
+// Trans. Am. Nucl. Soc., 1994, which takes O(log delta) time.
+func advanceLCG(state, delta, mult, inc uint128) uint128 {
+	var (
+		one     = uint128{0, 1}
+		accMult = one
+		accPlus uint128
+	)
+
+	for !delta.isZero() {
+		if delta.lo&1 != 0 {
+			accMult = accMult.mul(mult)
+			accPlus = accPlus.mul(mult).add(inc)
+		}
+		inc = mult.add(one).mul(inc)
+		mult = mult.mul(mult)
+
+		delta.lo = delta.lo>>1 | delta.hi<<63
+		delta.hi >>= 1
+	}
+
+	return accMult.mul(state).add(accPlus)
+}
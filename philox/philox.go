@@ -0,0 +1,198 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package philox implements the Philox4x64-10 counter-based random number
+// generator of Salmon et al., Parallel Random Numbers: As Easy as 1, 2, 3,
+// https://doi.org/10.1145/2063384.2063405.
+//
+// A counter-based generator is a keyed bijection applied to a counter.
+// Every value in its output stream can be computed independently of the
+// others, so distributed computations can derive their random numbers from
+// a key and an index without sharing generator state.
+package philox
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+const (
+	mult0 = 0xd2e7470ee14c6c93
+	mult1 = 0xca5a826395121157
+
+	// Key schedule constants: the golden ratio and sqrt(3)-1.
+	weyl0 = 0x9e3779b97f4a7c15
+	weyl1 = 0xbb67ae8584caa73b
+
+	rounds = 10
+)
+
+// Block returns the Philox4x64-10 bijection of the counter ctr under key.
+func Block(key [2]uint64, ctr [4]uint64) [4]uint64 {
+	k0, k1 := key[0], key[1]
+	c0, c1, c2, c3 := ctr[0], ctr[1], ctr[2], ctr[3]
+
+	for i := 0; i < rounds; i++ {
+		if i > 0 {
+			k0 += weyl0
+			k1 += weyl1
+		}
+		hi0, lo0 := bits.Mul64(mult0, c0)
+		hi1, lo1 := bits.Mul64(mult1, c2)
+		c0, c1, c2, c3 = hi1^c1^k0, lo1, hi0^c3^k1, lo0
+	}
+
+	return [4]uint64{c0, c1, c2, c3}
+}
+
+// At returns the value at position index in the stream of a Source
+// with the given key, i.e., the value that Uint64 returns after
+// New(key) and Seek(index).
+func At(key [2]uint64, index uint64) uint64 {
+	return Block(key, [4]uint64{index / 4})[index%4]
+}
+
+// A Source is a Philox4x64-10 random number generator.
+// Its state consists of a 128-bit key and a 256-bit counter.
+//
+// The output stream of a Source is the sequence of Blocks for
+// the counters 0, 1, 2, and so on, each of which produces four values.
+//
+// The zero Source is ready to use. It has the all-zero key.
+type Source struct {
+	key [2]uint64
+	ctr [4]uint64 // Counter of the next block.
+
+	buf [4]uint64 // Last block.
+	n   int       // Number of unused values in buf.
+}
+
+// New returns a Source with the given key, positioned at the start
+// of its stream.
+func New(key [2]uint64) *Source {
+	return &Source{key: key}
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Seed sets the key of s to {uint64(seed), 0} and positions s at
+// the start of its stream.
+func (s *Source) Seed(seed int64) {
+	*s = Source{key: [2]uint64{uint64(seed), 0}}
+}
+
+// Seek positions s at index in its stream, so that the next call
+// to Uint64 returns At(key, index).
+func (s *Source) Seek(index uint64) {
+	s.ctr = [4]uint64{index / 4}
+	s.n = 0
+	if r := int(index % 4); r != 0 {
+		s.refill()
+		s.n -= r
+	}
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Source) Uint64() uint64 {
+	if s.n == 0 {
+		s.refill()
+	}
+	x := s.buf[len(s.buf)-s.n]
+	s.n--
+	return x
+}
+
+func (s *Source) refill() {
+	s.buf = Block(s.key, s.ctr)
+	s.n = len(s.buf)
+	incr(&s.ctr)
+}
+
+func incr(ctr *[4]uint64) {
+	for i := range ctr {
+		ctr[i]++
+		if ctr[i] != 0 {
+			break
+		}
+	}
+}
+
+func decr(ctr *[4]uint64) {
+	for i := range ctr {
+		ctr[i]--
+		if ctr[i] != 1<<64-1 {
+			break
+		}
+	}
+}
+
+const (
+	header      = "philox4x64-10\x00\x00\x00"
+	marshalSize = len(header) + 2*8 + 4*8 + 8
+)
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format starts with a 16-byte header, followed by the key,
+// the counter of the next block and the number of values remaining in
+// the current block, all as 64-bit integers in little-endian format.
+//
+// The returned error is always nil.
+func (s *Source) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, marshalSize)
+	copy(data, header)
+
+	p := data[len(header):]
+	for _, x := range [...]uint64{
+		s.key[0], s.key[1],
+		s.ctr[0], s.ctr[1], s.ctr[2], s.ctr[3],
+		uint64(s.n),
+	} {
+		binary.LittleEndian.PutUint64(p, x)
+		p = p[8:]
+	}
+	return
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *Source) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) != marshalSize:
+		return errors.New("unmarshal philox: incorrect data length")
+	case string(data[:len(header)]) != header:
+		return errors.New("unmarshal philox: incorrect header")
+	}
+
+	var words [7]uint64
+	data = data[len(header):]
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data)
+		data = data[8:]
+	}
+	if words[6] > 4 {
+		return errors.New("unmarshal philox: invalid block position")
+	}
+
+	*s = Source{
+		key: [2]uint64{words[0], words[1]},
+		ctr: [4]uint64{words[2], words[3], words[4], words[5]},
+		n:   int(words[6]),
+	}
+	if s.n > 0 {
+		ctr := s.ctr
+		decr(&ctr)
+		s.buf = Block(s.key, ctr)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package philox_test
+
+import (
+	"encoding"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/philox"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*philox.Source)(nil)
+	_ encoding.BinaryUnmarshaler = (*philox.Source)(nil)
+	_ rand.Source64              = (*philox.Source)(nil)
+)
+
+func TestBlock(t *testing.T) {
+	t.Parallel()
+
+	const max = 1<<64 - 1
+
+	// Known-answer tests from Random123 1.09, kat_vectors.
+	for _, c := range []struct {
+		key      [2]uint64
+		ctr, out [4]uint64
+	}{
+		{
+			key: [2]uint64{0, 0},
+			ctr: [4]uint64{0, 0, 0, 0},
+			out: [4]uint64{
+				0x16554d9eca36314c, 0xdb20fe9d672d0fdc,
+				0xd7e772cee186176b, 0x7e68b68aec7ba23b},
+		},
+		{
+			key: [2]uint64{max, max},
+			ctr: [4]uint64{max, max, max, max},
+			out: [4]uint64{
+				0x87b092c3013fe90b, 0x438c3c67be8d0224,
+				0x9cc7d7c69cd777b6, 0xa09caebf594f0ba0},
+		},
+		{
+			key: [2]uint64{0x452821e638d01377, 0xbe5466cf34e90c6c},
+			ctr: [4]uint64{
+				0x243f6a8885a308d3, 0x13198a2e03707344,
+				0xa4093822299f31d0, 0x082efa98ec4e6c89},
+			out: [4]uint64{
+				0xa528f45403e61d95, 0x38c72dbd566e9788,
+				0xa5a1610e72fd18b5, 0x57bd43b5e52b7fe6},
+		},
+	} {
+		assert.Equal(t, c.out, philox.Block(c.key, c.ctr))
+	}
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	key := [2]uint64{0xdeadbeef, 0xfeedface}
+	s := philox.New(key)
+	for i := uint64(0); i < 100; i++ {
+		assert.Equal(t, philox.At(key, i), s.Uint64(), "index %d", i)
+	}
+
+	var zero philox.Source
+	assert.Equal(t, philox.Block([2]uint64{}, [4]uint64{})[0], zero.Uint64())
+
+	s.Seed(42)
+	assert.Equal(t, philox.At([2]uint64{42, 0}, 0), s.Uint64())
+}
+
+func TestSeek(t *testing.T) {
+	t.Parallel()
+
+	key := [2]uint64{1, 2}
+	s := philox.New(key)
+
+	for _, i := range []uint64{0, 1, 2, 3, 4, 5, 1000, 1<<62 + 3, 1<<64 - 1} {
+		s.Seek(i)
+		assert.Equal(t, philox.At(key, i), s.Uint64(), "index %d", i)
+	}
+
+	// Crossing into the second word of the counter.
+	marshaled, _ := s.MarshalBinary()
+	for i := 16 + 2*8; i < 16+3*8; i++ {
+		marshaled[i] = 0xff
+	}
+	marshaled[len(marshaled)-8] = 0
+
+	assert.Nil(t, s.UnmarshalBinary(marshaled))
+	for i := 0; i < 4; i++ {
+		s.Uint64()
+	}
+	assert.Equal(t, philox.Block(key, [4]uint64{0, 1})[0], s.Uint64())
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	s := philox.New([2]uint64{5, 6})
+
+	for i := 0; i < 10; i++ {
+		marshaled, err := s.MarshalBinary()
+		assert.Nil(t, err)
+
+		expect := []uint64{s.Uint64(), s.Uint64(), s.Uint64()}
+
+		var u philox.Source
+		err = u.UnmarshalBinary(marshaled)
+		assert.Nil(t, err)
+
+		actual := []uint64{u.Uint64(), u.Uint64(), u.Uint64()}
+		assert.Equal(t, expect, actual)
+	}
+
+	var s2 philox.Source
+	err := s2.UnmarshalBinary(nil)
+	assert.NotNil(t, err)
+	err = s2.UnmarshalBinary(make([]byte, 9*8))
+	assert.NotNil(t, err)
+}
+
+func BenchmarkSourceUint64(b *testing.B) {
+	r := philox.New([2]uint64{})
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkAt(b *testing.B) {
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		philox.At([2]uint64{}, uint64(i))
+	}
+}
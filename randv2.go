@@ -0,0 +1,86 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.22
+
+package randstat
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// FromV2 returns a rand.Source64 that draws its numbers from the
+// math/rand/v2 Source r, so that r can be passed to the functions in this
+// package and in package sampling. Its Int63 method returns the upper 63 bits
+// of r.Uint64 and its Seed method panics.
+//
+// If r already implements rand.Source64, FromV2 returns r itself.
+// The generators in this module all implement both interfaces.
+func FromV2(r randv2.Source) rand.Source64 {
+	if r64, ok := r.(rand.Source64); ok {
+		return r64
+	}
+	return v2Source{r}
+}
+
+type v2Source struct {
+	randv2.Source
+}
+
+func (r v2Source) Int63() int64 { return int64(r.Uint64() >> 1) }
+
+func (v2Source) Seed(int64) {
+	panic("randstat: cannot seed a math/rand/v2.Source")
+}
+
+// Uint64N returns a uniformly random integer from the range [0,n).
+// It panics if n == 0. r must not be nil.
+//
+// Uint64N is Uint64n for a math/rand/v2 Source: use Uint64N when r comes
+// from math/rand/v2, Uint64n when it is a math/rand Source64. Both produce
+// the same values from the same stream.
+func Uint64N(r randv2.Source, n uint64) uint64 {
+	if n == 0 {
+		panic("randstat.Uint64N: n == 0")
+	}
+	return uint64n(r, n)
+}
+
+// Uint32N returns a uniformly random integer from the range [0,n).
+// It panics if n == 0. r must not be nil.
+//
+// Uint32N is Uint32n for a math/rand/v2 Source. It consumes one Uint64 per
+// attempt, while Uint32n uses 32 bits of Int63 so that it works with any
+// math/rand Source, so the two produce different values from the same stream.
+func Uint32N(r randv2.Source, n uint32) uint32 {
+	if n == 0 {
+		panic("randstat.Uint32N: n == 0")
+	}
+	return uint32(uint64n(r, uint64(n)))
+}
+
+// N returns a uniformly random integer from the range [0,n).
+// It panics if n <= 0. r must not be nil.
+//
+// N is the counterpart of math/rand/v2.N, but takes a Source.
+func N[Int intType](r randv2.Source, n Int) Int {
+	if n <= 0 {
+		panic("randstat.N: n <= 0")
+	}
+	return Int(uint64n(r, uint64(n)))
+}
+
+type intType interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
@@ -0,0 +1,93 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.22
+
+package randstat_test
+
+import (
+	randv2 "math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/sampling"
+	"github.com/greatroar/randstat/splitmix64"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ randv2.Source = (*splitmix64.Source)(nil)
+	_ randv2.Source = (*xoshiro256.Source)(nil)
+)
+
+func TestFromV2(t *testing.T) {
+	t.Parallel()
+
+	x := xoshiro256.New(1)
+	assert.Same(t, x, randstat.FromV2(x))
+
+	r := randstat.FromV2(randv2.NewPCG(1, 2))
+	ref := randv2.NewPCG(1, 2)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, int64(ref.Uint64()>>1), r.Int63())
+	}
+	assert.Panics(t, func() { r.Seed(1) })
+
+	a := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	randstat.Shuffle(r, len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, a)
+
+	sample := sampling.Ints(5, 100, r, nil)
+	assert.Len(t, sample, 5)
+}
+
+func TestUint64N(t *testing.T) {
+	t.Parallel()
+
+	r := randv2.NewPCG(uint64(time.Now().UnixNano()), 0)
+
+	for _, max := range []uint64{1, 2, 198687, 1 << 32, 1<<60 + 1, 1<<64 - 1} {
+		for i := 0; i < 2000; i++ {
+			require.Less(t, randstat.Uint64N(r, max), max)
+		}
+	}
+	for _, max := range []uint32{1, 2, 198687, 1<<32 - 1} {
+		for i := 0; i < 2000; i++ {
+			require.Less(t, randstat.Uint32N(r, max), max)
+		}
+	}
+
+	assert.Panics(t, func() { randstat.Uint64N(r, 0) })
+	assert.Panics(t, func() { randstat.Uint32N(r, 0) })
+}
+
+func TestN(t *testing.T) {
+	t.Parallel()
+
+	r := randv2.NewChaCha8([32]byte{})
+
+	for i := 0; i < 2000; i++ {
+		i8 := randstat.N(r, int8(100))
+		require.True(t, 0 <= i8 && i8 < 100)
+		d := randstat.N(r, time.Hour)
+		require.True(t, 0 <= d && d < time.Hour)
+		u16 := randstat.N(r, uint16(1000))
+		require.True(t, u16 < 1000)
+	}
+
+	assert.Panics(t, func() { randstat.N(r, 0) })
+	assert.Panics(t, func() { randstat.N(r, -1) })
+}
@@ -0,0 +1,124 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/rand"
+
+	"github.com/greatroar/randstat/internal/sums"
+)
+
+// An Alias is an alias table for sampling from a categorical distribution
+// in constant time, using Vose's variant of Walker's alias method.
+//
+// An Alias is immutable, so it may be used by multiple goroutines
+// simultaneously, provided they use different random sources.
+type Alias struct {
+	// Index i is returned if the coin for column i is below thresh[i],
+	// otherwise alias[i] is returned. thresh[i] is a probability
+	// scaled to 2^64. Columns with probability one have alias[i] == i.
+	thresh []uint64
+	alias  []int
+}
+
+// NewAlias constructs an Alias that samples index i with probability
+// proportional to weights[i].
+//
+// It returns an error if there are no weights, if any weight is negative,
+// infinite or NaN, or if all weights are zero.
+func NewAlias(weights []float64) (*Alias, error) {
+	n := len(weights)
+	if n == 0 {
+		return nil, errors.New("sampling: no weights")
+	}
+
+	var maxw float64
+	for _, w := range weights {
+		if !(w >= 0 && w <= math.MaxFloat64) {
+			return nil, errors.New("sampling: weights must be finite and non-negative")
+		}
+		maxw = math.Max(maxw, w)
+	}
+	if maxw == 0 {
+		return nil, errors.New("sampling: all weights are zero")
+	}
+
+	// Divide by the largest weight so the sum cannot overflow.
+	var sum sums.Neumaier
+	for _, w := range weights {
+		sum.Add(w / maxw)
+	}
+	total := sum.Value()
+
+	a := &Alias{
+		thresh: make([]uint64, n),
+		alias:  make([]int, n),
+	}
+
+	// Scale the weights to mean one, then pair each column of less than one
+	// with a column of more than one to fill it up.
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w / maxw * (float64(n) / total)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+
+		a.thresh[s] = uint64(scaled[s] * 0x1p64)
+		a.alias[s] = l
+
+		scaled[l] = (scaled[l] + scaled[s]) - 1
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// What remains has probability one, up to rounding error.
+	for _, i := range append(small, large...) {
+		a.alias[i] = i
+	}
+
+	return a, nil
+}
+
+// Len returns the number of categories, i.e., the number of weights
+// that a was constructed with.
+func (a *Alias) Len() int { return len(a.alias) }
+
+// Sample returns a random index, with probabilities proportional
+// to the weights that a was constructed with.
+//
+// Sample consumes a single value from r, the high bits of which select
+// a column and the low bits of which decide between the column and its
+// alias. The probabilities are exact up to relative errors of order
+// a.Len()/2^64. r must not be nil.
+func (a *Alias) Sample(r rand.Source64) int {
+	i, coin := bits.Mul64(r.Uint64(), uint64(len(a.alias)))
+	if coin < a.thresh[i] {
+		return int(i)
+	}
+	return a.alias[i]
+}
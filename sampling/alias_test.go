@@ -0,0 +1,114 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/sampling"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlias(t *testing.T) {
+	t.Parallel()
+
+	r := xoshiro256.New(0xa11a5)
+
+	for _, weights := range [][]float64{
+		{1},
+		{0, 3, 0},
+		{1, 2, 3, 4},
+		{1e-300, 1e300, 5e299},
+		{math.MaxFloat64, math.MaxFloat64 / 2},
+		{.1, .1, .1, .1, .1, .1, .1, .1, .1, .1},
+		{1, 0, 100, 0, 0, 7, .5, 20, 3, 3, 0, 1},
+	} {
+		a, err := sampling.NewAlias(weights)
+		require.NoError(t, err)
+		require.Equal(t, len(weights), a.Len())
+
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+
+		x := make([]int64, 100000)
+		for i := range x {
+			k := a.Sample(r)
+			require.True(t, weights[k] > 0, "sampled index %d with weight zero", k)
+			x[i] = int64(k)
+		}
+		stattest.Discrete(t, x, func(k int64) float64 {
+			if k >= int64(len(weights)) {
+				return 0
+			}
+			return weights[k] / total
+		})
+	}
+}
+
+func TestAliasErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, weights := range [][]float64{
+		nil,
+		{0, 0},
+		{1, -1},
+		{1, math.NaN()},
+		{math.Inf(1)},
+	} {
+		a, err := sampling.NewAlias(weights)
+		assert.Nil(t, a)
+		assert.Error(t, err, "%v", weights)
+	}
+}
+
+func benchmarkWeights() []float64 {
+	r := xoshiro256.New(1)
+	weights := make([]float64, 1000)
+	for i := range weights {
+		weights[i] = float64(r.Uint64()>>11) + 1
+	}
+	return weights
+}
+
+func BenchmarkAlias(b *testing.B) {
+	a, _ := sampling.NewAlias(benchmarkWeights())
+	r := xoshiro256.New(1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Sample(r)
+	}
+}
+
+// Binary search over cumulative weights, for comparison.
+func BenchmarkAliasCumulative(b *testing.B) {
+	cum := benchmarkWeights()
+	for i := 1; i < len(cum); i++ {
+		cum[i] += cum[i-1]
+	}
+	r := xoshiro256.New(1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u := float64(r.Uint64()>>11) * 0x1p-53 * cum[len(cum)-1]
+		sort.SearchFloat64s(cum, u)
+	}
+}
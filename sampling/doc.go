@@ -1,2 +1,5 @@
 // Package sampling implements random sampling algorithms.
+//
+// With Go 1.23 or later, VaroptOf and ReservoirOf provide samplers for items
+// of a specific type, which avoid boxing items in interfaces.
 package sampling
@@ -83,7 +83,6 @@ const header = `// Code generated by generate_ints.go. DO NOT EDIT.
 package sampling
 
 import (
-	"math"
 	"math/rand"
 
 	"github.com/greatroar/randstat"
@@ -102,14 +101,12 @@ func ints{{.Bits}}_{{.Out}}(samplesize int, n {{.Type}}, r rand.Source{{.SrcBits
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+
+package sampling_test
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/greatroar/randstat/sampling"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// VaroptOf must make the same choices as Varopt.
+func TestVaroptOf(t *testing.T) {
+	t.Parallel()
+
+	const size = 50
+
+	v := sampling.NewVaropt(size, xoshiro256.New(0xc0ffee))
+	g := sampling.NewVaroptOf[int](size, xoshiro256.New(0xc0ffee))
+
+	weight := func(i int) float64 { return math.Pow(float64(1+i%97), 1.5) }
+
+	for i := 0; i < 10000; i++ {
+		vr := v.Show(i, weight(i))
+		gr, ok := g.Show(i, weight(i))
+		require.Equal(t, vr != nil, ok)
+		if ok {
+			require.Equal(t, vr, gr)
+		}
+	}
+
+	r, ok := g.Show(-1, 0)
+	assert.True(t, ok)
+	assert.Equal(t, -1, r)
+
+	require.Equal(t, v.Len(), g.Len())
+	assert.Equal(t, v.Threshold(), g.Threshold())
+	for i := 0; i < g.Len(); i++ {
+		assert.Equal(t, v.Item(i), g.Item(i))
+		assert.Equal(t, v.Weight(i), g.Weight(i))
+		assert.Equal(t, v.AdjustedWeight(i), g.AdjustedWeight(i))
+	}
+
+	i := 0
+	for x, w := range g.Items() {
+		assert.Equal(t, g.Item(i), x)
+		assert.Equal(t, g.AdjustedWeight(i), w)
+		i++
+	}
+	assert.Equal(t, g.Len(), i)
+
+	even := func(x int) bool { return x%2 == 0 }
+	sum, variance := g.EstimateSum(even)
+	vsum, vvariance := v.EstimateSum(func(x interface{}) bool { return even(x.(int)) })
+	assert.Equal(t, vsum, sum)
+	assert.Equal(t, vvariance, variance)
+
+	// VaroptOf and Varopt share a serialization format.
+	data, err := g.Marshal(func(x int) ([]byte, error) { return []byte(strconv.Itoa(x)), nil })
+	require.NoError(t, err)
+	vdata, err := v.Marshal(func(x interface{}) ([]byte, error) {
+		return []byte(strconv.Itoa(x.(int))), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, vdata, data)
+
+	restored, err := sampling.UnmarshalVaroptOf(data, func(b []byte) (int, error) {
+		return strconv.Atoi(string(b))
+	}, nil)
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		gr, gok := g.Show(-i, weight(i))
+		rr, rok := restored.Show(-i, weight(i))
+		require.Equal(t, gok, rok)
+		require.Equal(t, gr, rr)
+	}
+
+	other := sampling.NewVaroptOf[int](size, xoshiro256.New(1))
+	for i := 0; i < 100; i++ {
+		other.Show(10000+i, weight(i))
+	}
+	total, _ := g.EstimateSum(nil)
+	othertotal, _ := other.EstimateSum(nil)
+	g.Merge(other)
+	merged, _ := g.EstimateSum(nil)
+	assert.InEpsilon(t, total+othertotal, merged, 1e-9)
+	assert.Panics(t, func() { g.Merge(g) })
+}
+
+// ReservoirOf must make the same choices as Reservoir.
+func TestReservoirOf(t *testing.T) {
+	t.Parallel()
+
+	const samplesize = 10
+
+	g := sampling.NewReservoirOf[int](samplesize, xoshiro256.New(0xee))
+	v := sampling.NewReservoir(samplesize, xoshiro256.New(0xee))
+	for i := 0; i < 10000; i++ {
+		if i%1000 == 0 {
+			require.Equal(t, v.Skip(), g.Skip())
+		}
+		vr := v.Offer(i)
+		gr, ok := g.Offer(i)
+		require.Equal(t, vr != nil, ok)
+		if ok {
+			require.Equal(t, vr, gr)
+		}
+	}
+
+	s := sampling.NewReservoirOf[string](0, xoshiro256.New(1))
+	x, ok := s.Offer("x")
+	assert.True(t, ok)
+	assert.Equal(t, "x", x)
+	assert.Equal(t, 0, s.Len())
+	assert.EqualValues(t, math.MaxInt64, s.Skip())
+}
+
+func ExampleVaroptOf_Show_reuseMemory() {
+	// The return value from Show can be used to reuse allocated memory,
+	// without type assertions.
+
+	type item struct {
+		letter rune
+		index  int
+	}
+
+	sample := sampling.NewVaroptOf[*item](6, xoshiro256.New(1))
+	var x *item
+
+	for i, l := range "abcdefghijklmnopqrstuvwxyz" {
+		if x == nil {
+			x = new(item)
+		}
+		*x = item{letter: l, index: i}
+
+		// A rejected item is no longer in the sample and may be recycled.
+		x, _ = sample.Show(x, float64(1+i%3))
+	}
+
+	for it, w := range sample.Items() {
+		fmt.Printf("%c %.3f\n", it.letter, w)
+	}
+	// Output:
+	// o 8.500
+	// e 8.500
+	// b 8.500
+	// f 8.500
+	// n 8.500
+	// q 8.500
+}
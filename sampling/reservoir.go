@@ -0,0 +1,142 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+)
+
+// A Reservoir is a reservoir sampler for streams of unknown length.
+// It maintains a simple random sample of the items offered to it,
+// i.e., every item is included with the same probability.
+//
+// Reservoir implements Algorithm L of Li (1994), the algorithm used by Ints.
+// Once the sample is full, it decides in advance how many items to reject
+// before accepting the next one, so it needs random numbers only for the
+// items that it accepts. Callers can use Skip to discard these items
+// without offering them.
+type Reservoir struct {
+	reservoir
+	sample []interface{}
+}
+
+// reservoir implements Algorithm L for Reservoir and ReservoirOf.
+// It decides where items go; its users store them.
+type reservoir struct {
+	size int
+	r    rand.Source64
+
+	l    algorithmL
+	skip int64 // Number of items to reject before accepting one, or MaxInt64.
+}
+
+// NewReservoir constructs a Reservoir sampler.
+//
+// Random numbers are taken from r, or from an internal generator
+// bootstrapped from math.rand's global generator if r is nil.
+func NewReservoir(samplesize int, r rand.Source64) *Reservoir {
+	if samplesize < 0 {
+		panic("negative sample size")
+	}
+	return &Reservoir{
+		reservoir: newReservoir(samplesize, r),
+		sample:    make([]interface{}, 0, samplesize),
+	}
+}
+
+func newReservoir(samplesize int, r rand.Source64) reservoir {
+	s := reservoir{
+		size: samplesize,
+		r:    maybeXoshiro(r),
+		l:    newAlgorithmL(samplesize),
+	}
+	if samplesize == 0 {
+		s.skip = math.MaxInt64
+	}
+	return s
+}
+
+// Item returns the item at index i in the current sample.
+//
+// The index i must be at least zero and less than s.Len().
+// Items occur in the sample in random order.
+func (s *Reservoir) Item(i int) interface{} { return s.sample[i] }
+
+// Len returns the number of items currently in the sample.
+//
+// The number of items is the minimum of the desired sample size
+// and the number of items offered or skipped.
+func (s *Reservoir) Len() int { return len(s.sample) }
+
+// Offer presents x to s as a candidate for inclusion in its random sample.
+//
+// If x is rejected, Offer returns x. If x is accepted into the sample,
+// reject is set to the item evicted to make space for it, if any.
+// For the first samplesize items, reject will be nil.
+func (s *Reservoir) Offer(x interface{}) (reject interface{}) {
+	switch j := s.offer(len(s.sample)); {
+	case j < 0:
+		return x
+	case j == len(s.sample):
+		s.sample = append(s.sample, x)
+		return nil
+	default:
+		reject, s.sample[j] = s.sample[j], x
+		return reject
+	}
+}
+
+// offer decides on the next item offered to a sampler with n items in its
+// sample. It returns the index at which to store the item, which is n if
+// the sample is not yet full, or -1 if the item is rejected.
+func (s *reservoir) offer(n int) int {
+	switch {
+	case n < s.size:
+		if n+1 == s.size {
+			s.skip = nextSkip(&s.l, s.r)
+		}
+		return n
+
+	case s.skip > 0:
+		if s.skip < math.MaxInt64 {
+			s.skip--
+		}
+		return -1
+	}
+
+	j := randstat.Intn(s.r, s.size)
+	s.skip = nextSkip(&s.l, s.r)
+	return j
+}
+
+// Skip returns the number of upcoming items that s would reject, and
+// considers them rejected. The caller should discard that many items,
+// then offer the next one, which s will accept.
+//
+// Skip returns zero while the sample is not full. It returns math.MaxInt64
+// if s will not accept any more items, either because its sample size is
+// zero or because the chance of accepting another item in any practical
+// amount of time is negligible.
+func (s *Reservoir) Skip() int64 { return s.takeSkip() }
+
+// takeSkip implements Skip.
+func (s *reservoir) takeSkip() int64 {
+	n := s.skip
+	if n < math.MaxInt64 {
+		s.skip = 0
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+
+package sampling
+
+import (
+	"iter"
+	"math/rand"
+)
+
+// A ReservoirOf is a reservoir sampler for items of type T. It maintains
+// a simple random sample of the items offered to it, i.e., every item is
+// included with the same probability.
+//
+// It implements the same algorithm as Reservoir and, given the same random
+// number generator, makes the same choices, but it stores items without
+// boxing them in interfaces.
+type ReservoirOf[T any] struct {
+	reservoir
+	sample []T
+}
+
+// NewReservoirOf constructs a ReservoirOf sampler.
+//
+// Random numbers are taken from r, or from an internal generator
+// bootstrapped from math.rand's global generator if r is nil.
+func NewReservoirOf[T any](samplesize int, r rand.Source64) *ReservoirOf[T] {
+	if samplesize < 0 {
+		panic("negative sample size")
+	}
+	return &ReservoirOf[T]{
+		reservoir: newReservoir(samplesize, r),
+		sample:    make([]T, 0, samplesize),
+	}
+}
+
+// Item returns the item at index i in the current sample.
+//
+// The index i must be at least zero and less than s.Len().
+// Items occur in the sample in random order.
+func (s *ReservoirOf[T]) Item(i int) T { return s.sample[i] }
+
+// Items returns an iterator over the items in the current sample.
+//
+// The sample must not be modified during iteration.
+func (s *ReservoirOf[T]) Items() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range s.sample {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of items currently in the sample.
+//
+// The number of items is the minimum of the desired sample size
+// and the number of items offered or skipped.
+func (s *ReservoirOf[T]) Len() int { return len(s.sample) }
+
+// Offer presents x to s as a candidate for inclusion in its random sample.
+//
+// If x is rejected, Offer returns x and true. If x is accepted into the
+// sample, reject is set to the item evicted to make space for it, if any.
+// The boolean ok reports whether reject holds an item; for the first
+// samplesize items, it is false.
+func (s *ReservoirOf[T]) Offer(x T) (reject T, ok bool) {
+	switch j := s.offer(len(s.sample)); {
+	case j < 0:
+		return x, true
+	case j == len(s.sample):
+		s.sample = append(s.sample, x)
+		return reject, false
+	default:
+		reject, s.sample[j] = s.sample[j], x
+		return reject, true
+	}
+}
+
+// Skip returns the number of upcoming items that s would reject, and
+// considers them rejected. See Reservoir.Skip.
+func (s *ReservoirOf[T]) Skip() int64 { return s.takeSkip() }
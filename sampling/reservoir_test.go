@@ -0,0 +1,109 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/greatroar/randstat/sampling"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservoir(t *testing.T) {
+	t.Parallel()
+
+	const (
+		population = 100
+		samplesize = 10
+		trials     = 20000
+	)
+
+	r := xoshiro256.New(0x4e5)
+	freq := make([]float64, population)
+	for i := 0; i < trials; i++ {
+		s := sampling.NewReservoir(samplesize, r)
+		seen := make([]bool, population)
+		for j := 0; j < population; j++ {
+			reject := s.Offer(j)
+			if (reject == nil) != (j < samplesize) || reject != nil && seen[reject.(int)] {
+				t.Fatalf("Offer(%d) = %v", j, reject)
+			}
+			if reject != nil {
+				seen[reject.(int)] = true
+			}
+		}
+
+		// Every item is either rejected or in the sample.
+		require.Equal(t, samplesize, s.Len())
+		for k := 0; k < s.Len(); k++ {
+			x := s.Item(k).(int)
+			require.False(t, seen[x])
+			seen[x] = true
+			freq[x]++
+		}
+		require.NotContains(t, seen, false)
+	}
+
+	p := float64(samplesize) / population
+	for _, f := range freq {
+		assert.InDelta(t, p, f/trials, 5*math.Sqrt(p*(1-p)/trials))
+	}
+
+	s := sampling.NewReservoir(0, r)
+	assert.Equal(t, "x", s.Offer("x"))
+	assert.Equal(t, 0, s.Len())
+	assert.EqualValues(t, math.MaxInt64, s.Skip())
+}
+
+// Skipping items must produce the same sample as offering all of them.
+func TestReservoirSkip(t *testing.T) {
+	t.Parallel()
+
+	const (
+		n          = 1e6
+		samplesize = 10
+	)
+
+	all := sampling.NewReservoir(samplesize, xoshiro256.New(0x5c1))
+	for i := 0; i < n; i++ {
+		all.Offer(i)
+	}
+
+	skipping := sampling.NewReservoir(samplesize, xoshiro256.New(0x5c1))
+	offers := 0
+	for i := 0; i < n; i++ {
+		skip := skipping.Skip()
+		if i < samplesize {
+			require.Zero(t, skip)
+		}
+		if skip >= n-int64(i) {
+			break
+		}
+		i += int(skip)
+		reject := skipping.Offer(i)
+		offers++
+		require.NotEqual(t, i, reject, "item after skip rejected")
+	}
+
+	require.Equal(t, all.Len(), skipping.Len())
+	for i := 0; i < all.Len(); i++ {
+		assert.Equal(t, all.Item(i), skipping.Item(i))
+	}
+
+	// Expected number of acceptances is about samplesize*(1+log(n/samplesize)).
+	assert.Less(t, offers, 500)
+}
@@ -1,6 +1,7 @@
 package sampling
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/greatroar/randstat"
@@ -24,3 +25,30 @@ retry:
 	}
 	return x
 }
+
+// algorithmL computes the skips of Algorithm L from Li 1994,
+// Reservoir-Sampling Algorithms of Time Complexity O(n(1+log(N/n))),
+// ACM TOMS, https://doi.org/10.1145%2F198429.198435.
+type algorithmL struct {
+	w float64 // Largest random key in the sample.
+	k float64 // Inverse of the sample size.
+}
+
+func newAlgorithmL(samplesize int) algorithmL {
+	return algorithmL{w: 1, k: 1 / float64(samplesize)}
+}
+
+// skip returns the number of items to reject before accepting the next one
+// into a full sample. The result is an integer, but may be +Inf.
+func (l *algorithmL) skip(r rand.Source) float64 {
+	l.w *= math.Exp(math.Log(random01(r)) * l.k)
+	return math.Floor(math.Log(random01(r)) / math.Log1p(-l.w))
+}
+
+// nextSkip returns the next skip of l as an int64.
+func nextSkip(l *algorithmL, r rand.Source) int64 {
+	if skip := l.skip(r); skip < math.MaxInt64 {
+		return int64(skip)
+	}
+	return math.MaxInt64
+}
@@ -2,10 +2,14 @@ package sampling
 
 import (
 	"container/heap"
+	"encoding/binary"
+	"errors"
+	"math"
 	"math/rand"
 
 	"github.com/greatroar/randstat"
 	"github.com/greatroar/randstat/internal/sums"
+	"github.com/greatroar/randstat/xoshiro256"
 )
 
 // A Varopt is a weighted reservoir sampler. Items are included in its sample
@@ -13,6 +17,14 @@ import (
 //
 // Varopt implements the algorithm of https://arxiv.org/pdf/0803.0473.pdf.
 type Varopt struct {
+	varopt
+	items []interface{} // Indexed by slot.
+}
+
+// varopt implements the VarOpt algorithm for Varopt and VaroptOf. It only
+// keeps track of weights; its users store the items in a slice, indexed by
+// slots that varopt assigns. At most size+1 slots are in use at any time.
+type varopt struct {
 	// Reservoir, divided according to weight above or below the threshold.
 	large minWeight
 	small []item
@@ -22,6 +34,7 @@ type Varopt struct {
 	r         rand.Source64
 	size      int
 	threshold float64
+	spare     int // Slot for the next item shown.
 }
 
 // NewVaropt constructs a Varopt sampler.
@@ -33,18 +46,24 @@ func NewVaropt(samplesize int, r rand.Source64) *Varopt {
 		panic("negative sample size")
 	}
 
-	r = maybeXoshiro(r)
+	v := &Varopt{items: make([]interface{}, 0, 1+samplesize)}
+	v.init(samplesize, samplesize, maybeXoshiro(r))
+	return v
+}
 
-	reservoir := make([]item, 3+3*samplesize)
-	small := reservoir[:1+samplesize]
-	large := reservoir[1+samplesize : 2+2*samplesize]
-	smallbuf := reservoir[2+2*samplesize:]
+// init initializes v with room for capacity items before its slices
+// need to grow.
+func (v *varopt) init(samplesize, capacity int, r rand.Source64) {
+	reservoir := make([]item, 3+3*capacity)
+	small := reservoir[:1+capacity]
+	large := reservoir[1+capacity : 2+2*capacity]
+	smallbuf := reservoir[2+2*capacity:]
 
-	return &Varopt{
-		large: large[: 0 : 1+samplesize],
-		small: small[: 0 : 1+samplesize],
+	*v = varopt{
+		large: large[: 0 : 1+capacity],
+		small: small[: 0 : 1+capacity],
 
-		smallbuf: smallbuf[: 0 : 1+samplesize],
+		smallbuf: smallbuf[: 0 : 1+capacity],
 
 		r:    r,
 		size: samplesize,
@@ -52,8 +71,25 @@ func NewVaropt(samplesize int, r rand.Source64) *Varopt {
 }
 
 type item struct {
-	v interface{}
-	w float64
+	slot int
+	w    float64
+}
+
+// Merge adds the sample of other to that of v, so that v becomes a sample
+// of the items shown to either, with v's sample size. other is not modified.
+//
+// Merge shows the items in the sample of other to v, each with its adjusted
+// weight. By a result of Cohen et al. (see Varopt), this yields a VarOpt
+// sample of the union of the two streams, so adjusted weights and
+// EstimateSum remain unbiased. Merged items carry their adjusted weights
+// from other as their weights.
+func (v *Varopt) Merge(other *Varopt) {
+	if other == v {
+		panic("Varopt merged with itself")
+	}
+	for i := 0; i < other.Len(); i++ {
+		v.Show(other.Item(i), other.AdjustedWeight(i))
+	}
 }
 
 // Show presents x to v as a candidate for inclusion in its random sample.
@@ -64,19 +100,42 @@ type item struct {
 // If x is accepted into the sample, reject is set to the item evicted to make
 // space for it, if any. For the first samplesize items, reject will be nil.
 func (v *Varopt) Show(x interface{}, w float64) (reject interface{}) {
+	slot := v.spare
+	evict := v.show(w)
+	if evict == slot {
+		return x
+	}
+
+	if slot == len(v.items) {
+		v.items = append(v.items, x)
+	} else {
+		v.items[slot] = x
+	}
+	if evict < 0 {
+		return nil
+	}
+	reject, v.items[evict] = v.items[evict], nil // Allow garbage collection.
+	return reject
+}
+
+// show presents an item with weight w, stored in slot v.spare, to v.
+// It returns the slot of the item evicted from the sample, which is v.spare
+// if the new item is rejected, or -1 if no item is evicted.
+func (v *varopt) show(w float64) (evict int) {
 	switch {
 	case w == 0:
-		return x
+		return v.spare
 
 	case w < 0:
 		panic("negative weight")
 
-	case v.Len() < v.size:
-		v.large = append(v.large, item{x, w})
+	case v.len() < v.size:
+		v.large = append(v.large, item{v.spare, w})
+		v.spare = v.len()
 		if len(v.large) == v.size {
 			heap.Init(&v.large)
 		}
-		return nil
+		return -1
 	}
 
 	//var wsum sums.Neumaier
@@ -86,11 +145,11 @@ func (v *Varopt) Show(x interface{}, w float64) (reject interface{}) {
 	small := v.smallbuf[:0]
 
 	if w > v.threshold {
-		// heap.Push(&v.large, item{x, w})
-		v.large = append(v.large, item{x, w})
+		// heap.Push(&v.large, item{v.spare, w})
+		v.large = append(v.large, item{v.spare, w})
 		heap.Fix(&v.large, len(v.large)-1)
 	} else {
-		small = append(small, item{x, w})
+		small = append(small, item{v.spare, w})
 		wsum.Add(w)
 	}
 
@@ -116,46 +175,320 @@ func (v *Varopt) Show(x interface{}, w float64) (reject interface{}) {
 		j++
 	}
 
-	var evict item
+	var lost item
 	if r.Value() < 0 {
-		evict, small = remove(small, j-1)
+		lost, small = remove(small, j-1)
 	} else {
 		j = randstat.Intn(v.r, len(v.small))
-		evict, v.small = remove(v.small, j)
+		lost, v.small = remove(v.small, j)
 	}
 
 	v.small = append(v.small, small...)
-	for i := range small {
-		small[i].v = nil // Allow garbage collection.
-	}
 	v.smallbuf = small
 	v.threshold = t
+	v.spare = lost.slot
+
+	return lost.slot
+}
+
+// AdjustedWeight returns the adjusted weight of the item at index i in
+// the current sample: its own weight if that exceeds v.Threshold(),
+// otherwise the threshold.
+//
+// The adjusted weight of an item, or zero for items not in the sample,
+// is an unbiased estimate of its weight (the Horvitz-Thompson estimator).
+// The adjusted weights of the sample sum to the total weight of the items
+// that have been shown.
+//
+// The index i must be at least zero and less than s.Len().
+func (v *Varopt) AdjustedWeight(i int) float64 { return v.adjustedWeight(i) }
 
-	return evict.v
+func (v *varopt) adjustedWeight(i int) float64 {
+	if i < len(v.large) {
+		return v.large[i].w
+	}
+	_ = v.small[i-len(v.large)] // Bounds check.
+	return v.threshold
+}
+
+// EstimateSum estimates the total weight of the items shown to v for which
+// pred returns true, by summing their adjusted weights. If pred is nil,
+// all items are counted.
+//
+// The estimate is unbiased. The variance is an unbiased estimate of the
+// sum of the variances of the adjusted weights of the matching items,
+// which is an upper bound on the variance of the sum because VarOpt
+// samples are negatively correlated.
+func (v *Varopt) EstimateSum(pred func(interface{}) bool) (sum, variance float64) {
+	if pred == nil {
+		return v.estimateSum(nil)
+	}
+	return v.estimateSum(func(slot int) bool { return pred(v.items[slot]) })
+}
+
+// estimateSum implements EstimateSum, with pred taking the slot of an item.
+func (v *varopt) estimateSum(pred func(slot int) bool) (sum, variance float64) {
+	var s, vs sums.Neumaier
+	for _, it := range v.large {
+		if pred == nil || pred(it.slot) {
+			s.Add(it.w)
+		}
+	}
+
+	// Small items are included with probability w/threshold. The variance
+	// of an adjusted weight is w*(threshold-w), for which
+	// threshold*(threshold-w) is an unbiased estimate when the item is
+	// included.
+	t := v.threshold
+	for _, it := range v.small {
+		if pred == nil || pred(it.slot) {
+			s.Add(t)
+			vs.Add(t * (t - it.w))
+		}
+	}
+	return s.Value(), vs.Value()
 }
 
 // Item returns the item at index i in the current sample.
 //
 // The index i must be at least zero and less than s.Len().
 // Items occur in the sample in random order.
-func (v *Varopt) Item(i int) interface{} {
+func (v *Varopt) Item(i int) interface{} { return v.items[v.slot(i)] }
+
+// slot returns the slot of the item at index i in the current sample.
+func (v *varopt) slot(i int) int {
 	if i < len(v.large) {
-		return v.large[i].v
+		return v.large[i].slot
 	}
-	return v.small[i-len(v.large)].v
+	return v.small[i-len(v.large)].slot
 }
 
 // Len returns the number of items currently in the sample.
 //
 // The number of items is the minimum of the desired sample size
 // and the number of items Shown with positive weight.
-func (v *Varopt) Len() int { return len(v.large) + len(v.small) }
+func (v *Varopt) Len() int { return v.len() }
+
+func (v *varopt) len() int { return len(v.large) + len(v.small) }
+
+// Threshold returns the current threshold of v. Items with weight above
+// the threshold are included in the sample with probability one, those
+// with smaller weight w with probability w/v.Threshold().
+//
+// The threshold is zero until more than the desired sample size of items
+// have been shown.
+func (v *Varopt) Threshold() float64 { return v.threshold }
+
+// Weight returns the weight of the item at index i in the current sample,
+// as it was passed to Show, or the adjusted weight it had in the sample
+// that it was merged from.
+//
+// The index i must be at least zero and less than s.Len().
+func (v *Varopt) Weight(i int) float64 { return v.weight(i) }
+
+func (v *varopt) weight(i int) float64 {
+	if i < len(v.large) {
+		return v.large[i].w
+	}
+	return v.small[i-len(v.large)].w
+}
+
+const (
+	varoptHeader = "randstat varopt\x00"
+	maxInt       = int(^uint(0) >> 1)
+
+	// Relative error in the threshold tolerated by UnmarshalVaropt.
+	thresholdSlack = 1e-9
+)
+
+// Marshal encodes v in a binary format for serialization,
+// calling encodeItem to encode each item in the sample.
+//
+// If the random number generator of v is a *xoshiro256.Source, as it is
+// when NewVaropt is passed a nil generator, its state is included.
+// A Varopt restored from the result by UnmarshalVaropt then makes exactly
+// the same choices as v would. The state of other generators is not
+// included.
+//
+// The format starts with a 16-byte header, followed by the sample size,
+// the threshold, the numbers of items above and below the threshold and
+// the length of the generator state, then the generator state and the
+// items. Each item is encoded as its weight, the length of its encoding
+// and its encoding. All numbers are 64-bit and little-endian.
+func (v *Varopt) Marshal(encodeItem func(x interface{}) ([]byte, error)) (data []byte, err error) {
+	return v.marshal(func(slot int) ([]byte, error) { return encodeItem(v.items[slot]) })
+}
+
+// marshal implements Marshal, with encodeItem taking the slot of an item.
+func (v *varopt) marshal(encodeItem func(slot int) ([]byte, error)) (data []byte, err error) {
+	var state []byte
+	if x, ok := v.r.(*xoshiro256.Source); ok {
+		state, _ = x.MarshalBinary()
+	}
+
+	data = append(data, varoptHeader...)
+	data = appendUint64(data, uint64(v.size))
+	data = appendUint64(data, math.Float64bits(v.threshold))
+	data = appendUint64(data, uint64(len(v.large)))
+	data = appendUint64(data, uint64(len(v.small)))
+	data = appendUint64(data, uint64(len(state)))
+	data = append(data, state...)
+
+	for _, items := range [][]item{v.large, v.small} {
+		for _, it := range items {
+			enc, err := encodeItem(it.slot)
+			if err != nil {
+				return nil, err
+			}
+			data = appendUint64(data, math.Float64bits(it.w))
+			data = appendUint64(data, uint64(len(enc)))
+			data = append(data, enc...)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalVaropt decodes a Varopt from the binary format used by
+// Varopt.Marshal, calling decodeItem to decode each item.
+//
+// If r is nil, the generator is restored from data. That requires
+// the original generator to have been a *xoshiro256.Source. Otherwise,
+// r is used as the generator of the restored Varopt and any generator
+// state in data is ignored. To continue with a generator of another type,
+// the caller must save and restore it separately.
+//
+// UnmarshalVaropt returns an error if data is not a valid encoding.
+func UnmarshalVaropt(data []byte, decodeItem func([]byte) (interface{}, error), r rand.Source64) (*Varopt, error) {
+	v := new(Varopt)
+	err := v.unmarshal(data, r, func(enc []byte) error {
+		x, err := decodeItem(enc)
+		v.items = append(v.items, x)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// unmarshal implements UnmarshalVaropt. It calls decodeItem for the items
+// in order of their slots, starting at zero.
+func (v *varopt) unmarshal(data []byte, r rand.Source64, decodeItem func([]byte) error) error {
+	if len(data) < len(varoptHeader) || string(data[:len(varoptHeader)]) != varoptHeader {
+		return errors.New("unmarshal varopt: incorrect header")
+	}
+	d := decoder{data: data[len(varoptHeader):]}
+
+	size := d.uint64()
+	threshold := math.Float64frombits(d.uint64())
+	nlarge, nsmall := d.uint64(), d.uint64()
+	state := d.bytes(d.uint64())
+	switch {
+	case d.err != nil:
+		return d.err
+	case size > uint64(maxInt):
+		return errors.New("unmarshal varopt: sample size too large")
+	case nlarge > size || nsmall > size-nlarge:
+		return errors.New("unmarshal varopt: too many items")
+	case nlarge+nsmall > uint64(len(d.data)/16):
+		// Each item takes at least 16 bytes.
+		return errors.New("unmarshal varopt: truncated data")
+	case !(threshold >= 0 && threshold <= math.MaxFloat64):
+		return errors.New("unmarshal varopt: invalid threshold")
+	case nlarge+nsmall < size && (nsmall > 0 || threshold > 0):
+		// Until the sample is full, all items are above the threshold of 0.
+		return errors.New("unmarshal varopt: sample not full")
+	}
+	n := int(nlarge + nsmall)
+
+	if r == nil {
+		if len(state) == 0 {
+			return errors.New("unmarshal varopt: no generator state")
+		}
+		x := new(xoshiro256.Source)
+		if err := x.UnmarshalBinary(state); err != nil {
+			return err
+		}
+		r = x
+	}
+
+	// Allocate for the items present, not the sample size, which need not
+	// be justified by data.
+	v.init(int(size), n, r)
+	v.threshold = threshold
+	v.spare = n
+	for i := 0; i < n; i++ {
+		w := math.Float64frombits(d.uint64())
+		enc := d.bytes(d.uint64())
+		if d.err != nil {
+			return d.err
+		}
+		large := i < int(nlarge)
+		switch {
+		case !(w > 0 && w <= math.MaxFloat64):
+			return errors.New("unmarshal varopt: invalid weight")
+		case large && w < threshold*(1-thresholdSlack),
+			!large && w > threshold*(1+thresholdSlack):
+			// Show puts the items on the right side of the threshold,
+			// but computes it with rounding errors.
+			return errors.New("unmarshal varopt: weight on wrong side of threshold")
+		}
+
+		if err := decodeItem(enc); err != nil {
+			return err
+		}
+		if large {
+			v.large = append(v.large, item{i, w})
+		} else {
+			v.small = append(v.small, item{i, w})
+		}
+	}
+
+	if len(d.data) > 0 {
+		return errors.New("unmarshal varopt: trailing data")
+	}
+	if n == v.size {
+		// Show keeps the large items in a heap once the sample is full.
+		// This is a no-op for valid data, which is already a heap.
+		heap.Init(&v.large)
+	}
+	return nil
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+// A decoder reads from data, recording the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) bytes(n uint64) []byte {
+	if d.err != nil || n > uint64(len(d.data)) {
+		d.err = errors.New("unmarshal varopt: truncated data")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
 
 func remove(a []item, i int) (item, []item) {
 	x := a[i]
 	n := len(a) - 1
 	a[i] = a[n]
-	a[n].v = nil // Allow garbage collection.
 	return x, a[:n]
 }
 
@@ -0,0 +1,163 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+
+package sampling
+
+import (
+	"iter"
+	"math/rand"
+)
+
+// A VaroptOf is a weighted reservoir sampler for items of type T.
+//
+// It implements the same algorithm as Varopt and, given the same random
+// number generator, makes the same choices, but it stores items without
+// boxing them in interfaces.
+type VaroptOf[T any] struct {
+	varopt
+	items []T // Indexed by slot.
+}
+
+// NewVaroptOf constructs a VaroptOf sampler.
+//
+// Random numbers are taken from r, or from an internal generator
+// bootstrapped from math.rand's global generator if r is nil.
+func NewVaroptOf[T any](samplesize int, r rand.Source64) *VaroptOf[T] {
+	if samplesize < 0 {
+		panic("negative sample size")
+	}
+
+	v := &VaroptOf[T]{items: make([]T, 0, 1+samplesize)}
+	v.init(samplesize, samplesize, maybeXoshiro(r))
+	return v
+}
+
+// AdjustedWeight returns the adjusted weight of the item at index i in
+// the current sample. See Varopt.AdjustedWeight.
+//
+// The index i must be at least zero and less than s.Len().
+func (v *VaroptOf[T]) AdjustedWeight(i int) float64 { return v.adjustedWeight(i) }
+
+// EstimateSum estimates the total weight of the items shown to v for which
+// pred returns true. If pred is nil, all items are counted.
+// See Varopt.EstimateSum.
+func (v *VaroptOf[T]) EstimateSum(pred func(T) bool) (sum, variance float64) {
+	if pred == nil {
+		return v.estimateSum(nil)
+	}
+	return v.estimateSum(func(slot int) bool { return pred(v.items[slot]) })
+}
+
+// Item returns the item at index i in the current sample.
+//
+// The index i must be at least zero and less than s.Len().
+// Items occur in the sample in random order.
+func (v *VaroptOf[T]) Item(i int) T { return v.items[v.slot(i)] }
+
+// Items returns an iterator over the items in the current sample
+// and their adjusted weights.
+//
+// The sample must not be modified during iteration.
+func (v *VaroptOf[T]) Items() iter.Seq2[T, float64] {
+	return func(yield func(T, float64) bool) {
+		for i := 0; i < v.len(); i++ {
+			if !yield(v.Item(i), v.adjustedWeight(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of items currently in the sample.
+//
+// The number of items is the minimum of the desired sample size
+// and the number of items Shown with positive weight.
+func (v *VaroptOf[T]) Len() int { return v.len() }
+
+// Marshal encodes v in a binary format for serialization,
+// calling encodeItem to encode each item in the sample.
+//
+// The format is that of Varopt.Marshal, so the result can also be decoded
+// by UnmarshalVaropt, and vice versa.
+func (v *VaroptOf[T]) Marshal(encodeItem func(x T) ([]byte, error)) (data []byte, err error) {
+	return v.marshal(func(slot int) ([]byte, error) { return encodeItem(v.items[slot]) })
+}
+
+// UnmarshalVaroptOf decodes a VaroptOf from the binary format used by
+// VaroptOf.Marshal, calling decodeItem to decode each item.
+// See UnmarshalVaropt for the meaning of r.
+//
+// UnmarshalVaroptOf returns an error if data is not a valid encoding.
+func UnmarshalVaroptOf[T any](data []byte, decodeItem func([]byte) (T, error), r rand.Source64) (*VaroptOf[T], error) {
+	v := new(VaroptOf[T])
+	err := v.unmarshal(data, r, func(enc []byte) error {
+		x, err := decodeItem(enc)
+		v.items = append(v.items, x)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Merge adds the sample of other to that of v, so that v becomes a sample
+// of the items shown to either, with v's sample size. other is not modified.
+// See Varopt.Merge.
+func (v *VaroptOf[T]) Merge(other *VaroptOf[T]) {
+	if other == v {
+		panic("Varopt merged with itself")
+	}
+	for i := 0; i < other.Len(); i++ {
+		v.Show(other.Item(i), other.AdjustedWeight(i))
+	}
+}
+
+// Show presents x to v as a candidate for inclusion in its random sample.
+//
+// An item with zero weight is always rejected. A negative weight causes Show
+// to panic.
+//
+// If x is accepted into the sample, reject is set to the item evicted to make
+// space for it, if any. The boolean ok reports whether reject holds an item,
+// which is x itself if x was not accepted. For the first samplesize items,
+// ok is false.
+func (v *VaroptOf[T]) Show(x T, w float64) (reject T, ok bool) {
+	slot := v.spare
+	evict := v.show(w)
+	if evict == slot {
+		return x, true
+	}
+
+	if slot == len(v.items) {
+		v.items = append(v.items, x)
+	} else {
+		v.items[slot] = x
+	}
+	if evict < 0 {
+		return reject, false
+	}
+	var zero T
+	reject, v.items[evict] = v.items[evict], zero // Allow garbage collection.
+	return reject, true
+}
+
+// Threshold returns the current threshold of v. See Varopt.Threshold.
+func (v *VaroptOf[T]) Threshold() float64 { return v.threshold }
+
+// Weight returns the weight of the item at index i in the current sample.
+// See Varopt.Weight.
+//
+// The index i must be at least zero and less than s.Len().
+func (v *VaroptOf[T]) Weight(i int) float64 { return v.weight(i) }
@@ -1,8 +1,11 @@
 package sampling_test
 
 import (
+	"encoding/binary"
+	"errors"
 	"math"
 	"math/rand"
+	"strconv"
 	"testing"
 	"time"
 
@@ -106,6 +109,288 @@ func TestVaroptStats(t *testing.T) {
 	assert.Less(t, errNorm, .12)
 }
 
+func TestVaroptAdjustedWeight(t *testing.T) {
+	t.Parallel()
+
+	r := xoshiro256.New(0x7a60)
+	s := sampling.NewVaropt(100, r)
+
+	var total float64
+	for i := 0; i < 50; i++ {
+		w := 10 * randstat.Float64(r)
+		total += w
+		s.Show(i, w)
+	}
+	// Not yet full: all items are in the sample with their own weights.
+	assert.Equal(t, 0., s.Threshold())
+	for i := 0; i < s.Len(); i++ {
+		assert.Equal(t, s.Weight(i), s.AdjustedWeight(i))
+	}
+
+	for i := 50; i < 10000; i++ {
+		w := math.Pow(randstat.Float64(r), -2) // Heavy-tailed.
+		total += w
+		s.Show(i, w)
+	}
+
+	tau := s.Threshold()
+	assert.Greater(t, tau, 0.)
+	var adjusted float64
+	for i := 0; i < s.Len(); i++ {
+		adjusted += s.AdjustedWeight(i)
+		assert.Equal(t, math.Max(tau, s.Weight(i)), s.AdjustedWeight(i))
+	}
+	assert.InEpsilon(t, total, adjusted, 1e-9)
+
+	sum, _ := s.EstimateSum(nil)
+	assert.InEpsilon(t, total, sum, 1e-9)
+	sum, variance := s.EstimateSum(func(interface{}) bool { return false })
+	assert.Equal(t, 0., sum)
+	assert.Equal(t, 0., variance)
+}
+
+// Checks that EstimateSum is unbiased and that its variance estimate
+// is conservative.
+func TestVaroptEstimateSum(t *testing.T) {
+	t.Parallel()
+
+	const (
+		population = 1000
+		samplesize = 50
+		trials     = 2000
+	)
+
+	weights := make([]float64, population)
+	r := xoshiro256.New(0x5eed)
+	for i := range weights {
+		weights[i] = math.Pow(randstat.Float64(r), -1.5)
+	}
+
+	pred := func(x interface{}) bool { return x.(int)%3 == 0 }
+	var truth float64
+	for i, w := range weights {
+		if pred(i) {
+			truth += w
+		}
+	}
+
+	var mean, sumsq, meanVar float64
+	for i := 0; i < trials; i++ {
+		s := sampling.NewVaropt(samplesize, r)
+		for j, w := range weights {
+			s.Show(j, w)
+		}
+		est, variance := s.EstimateSum(pred)
+		mean += est
+		sumsq += est * est
+		meanVar += variance
+	}
+	mean /= trials
+	meanVar /= trials
+	empiricalVar := sumsq/trials - mean*mean
+
+	assert.InDelta(t, truth, mean, 5*math.Sqrt(empiricalVar/trials))
+	assert.Greater(t, meanVar, .8*empiricalVar)
+}
+
+// ippsThreshold returns the threshold tau such that the probabilities
+// min(1, w/tau) sum to k.
+func ippsThreshold(weights []float64, k int) float64 {
+	lo, hi := 0., 0.
+	for _, w := range weights {
+		hi += w
+	}
+	for i := 0; i < 200; i++ {
+		tau := (lo + hi) / 2
+		var sum float64
+		for _, w := range weights {
+			sum += math.Min(1, w/tau)
+		}
+		if sum > float64(k) {
+			lo = tau
+		} else {
+			hi = tau
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// Checks that merging samples of two halves of a stream gives the same
+// inclusion probabilities as sampling the stream in one pass.
+func TestVaroptMerge(t *testing.T) {
+	t.Parallel()
+
+	const (
+		population = 60
+		samplesize = 10
+		trials     = 20000
+	)
+
+	weights := make([]float64, population)
+	for i := range weights {
+		weights[i] = 1 + float64(i%7)
+	}
+	weights[0], weights[1] = 40, 100 // Always included.
+
+	tau := ippsThreshold(weights, samplesize)
+
+	r := xoshiro256.New(0x3e76e)
+	freq := make([]float64, population)
+	var merged *sampling.Varopt
+	for i := 0; i < trials; i++ {
+		even := sampling.NewVaropt(samplesize, r)
+		odd := sampling.NewVaropt(samplesize, r)
+		for j, w := range weights {
+			if j%2 == 0 {
+				even.Show(j, w)
+			} else {
+				odd.Show(j, w)
+			}
+		}
+
+		even.Merge(odd)
+		merged = even
+		require.Equal(t, samplesize, merged.Len())
+		for j := 0; j < merged.Len(); j++ {
+			freq[merged.Item(j).(int)]++
+		}
+	}
+
+	assert.InEpsilon(t, tau, merged.Threshold(), 1e-9)
+	for i, w := range weights {
+		p := math.Min(1, w/tau)
+		assert.InDelta(t, p, freq[i]/trials, 5*math.Sqrt(p*(1-p)/trials)+1e-12)
+	}
+
+	sum, _ := merged.EstimateSum(nil)
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	assert.InEpsilon(t, total, sum, 1e-9)
+
+	assert.Panics(t, func() { merged.Merge(merged) })
+}
+
+func TestVaroptMarshal(t *testing.T) {
+	t.Parallel()
+
+	encode := func(x interface{}) ([]byte, error) {
+		return []byte(strconv.Itoa(x.(int))), nil
+	}
+	decode := func(b []byte) (interface{}, error) { return strconv.Atoi(string(b)) }
+
+	weight := func(i int) float64 { return float64(1 + i%13) }
+
+	for _, shown := range []int{0, 5, 1000} {
+		s := sampling.NewVaropt(20, nil)
+		for i := 0; i < shown; i++ {
+			s.Show(i, weight(i))
+		}
+
+		data, err := s.Marshal(encode)
+		require.NoError(t, err)
+		restored, err := sampling.UnmarshalVaropt(data, decode, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, s.Len(), restored.Len())
+		require.Equal(t, s.Threshold(), restored.Threshold())
+
+		// Both continue identically.
+		for i := shown; i < shown+1000; i++ {
+			require.Equal(t, s.Show(i, weight(i)), restored.Show(i, weight(i)))
+		}
+		for i := 0; i < s.Len(); i++ {
+			require.Equal(t, s.Item(i), restored.Item(i))
+			require.Equal(t, s.Weight(i), restored.Weight(i))
+		}
+	}
+
+	// Generators that are not xoshiro256 need to be passed in.
+	s := sampling.NewVaropt(3, rand.NewSource(1).(rand.Source64))
+	s.Show(1, 1)
+	data, err := s.Marshal(encode)
+	require.NoError(t, err)
+	_, err = sampling.UnmarshalVaropt(data, decode, nil)
+	assert.Error(t, err)
+	restored, err := sampling.UnmarshalVaropt(data, decode, xoshiro256.New(1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored.Item(0))
+
+	errItem := errors.New("item")
+	_, err = s.Marshal(func(interface{}) ([]byte, error) { return nil, errItem })
+	assert.Equal(t, errItem, err)
+	_, err = sampling.UnmarshalVaropt(data, func([]byte) (interface{}, error) {
+		return nil, errItem
+	}, xoshiro256.New(1))
+	assert.Equal(t, errItem, err)
+
+	for i := 0; i < len(data); i++ {
+		_, err = sampling.UnmarshalVaropt(data[:i], decode, xoshiro256.New(1))
+		assert.Error(t, err)
+	}
+	_, err = sampling.UnmarshalVaropt(append(data, 0), decode, xoshiro256.New(1))
+	assert.Error(t, err)
+}
+
+// Not parallel, because of AllocsPerRun.
+func TestVaroptUnmarshalInvalid(t *testing.T) {
+	encode := func(x interface{}) ([]byte, error) { return []byte{byte(x.(int))}, nil }
+	decode := func(b []byte) (interface{}, error) { return int(b[0]), nil }
+
+	// A huge sample size must not cause a huge allocation:
+	// only the items present are allocated for.
+	empty := make([]byte, 56)
+	copy(empty, "randstat varopt\x00")
+	binary.LittleEndian.PutUint64(empty[16:], math.MaxInt32-1)
+	allocs := testing.AllocsPerRun(10, func() {
+		v, err := sampling.UnmarshalVaropt(empty, decode, xoshiro256.New(1))
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Len())
+	})
+	assert.Less(t, allocs, 10.)
+
+	binary.LittleEndian.PutUint64(empty[32:], math.MaxInt32-1) // nlarge.
+	_, err := sampling.UnmarshalVaropt(empty, decode, xoshiro256.New(1))
+	assert.Error(t, err)
+
+	s := sampling.NewVaropt(4, nil)
+	s.Show(0, 1e6)
+	for i := 1; i < 100; i++ {
+		s.Show(i, float64(1+i%5))
+	}
+	require.Greater(t, s.Threshold(), 5.)
+	require.Less(t, s.Threshold(), 1e6)
+	data, err := s.Marshal(encode)
+	require.NoError(t, err)
+
+	modify := func(offset int, x uint64) []byte {
+		d := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(d[offset:], x)
+		return d
+	}
+	for _, d := range [][]byte{
+		modify(16, 5),                     // Sample size larger than the item count.
+		modify(24, math.Float64bits(2e6)), // Large items below the threshold.
+		modify(24, math.Float64bits(1)),   // Small items above the threshold.
+		modify(24, math.Float64bits(-1)),  // Negative threshold.
+		modify(24, math.Float64bits(math.NaN())),
+	} {
+		_, err := sampling.UnmarshalVaropt(d, decode, nil)
+		assert.Error(t, err)
+	}
+	_, err = sampling.UnmarshalVaropt(data, decode, nil)
+	assert.NoError(t, err)
+
+	// Only xoshiro256.Source state is stored.
+	s = sampling.NewVaropt(4, xoshiro256.NewPlusPlus(1))
+	s.Show(1, 1)
+	data, err = s.Marshal(encode)
+	require.NoError(t, err)
+	_, err = sampling.UnmarshalVaropt(data, decode, nil)
+	assert.Error(t, err)
+}
+
 func benchmarkVaropt(b *testing.B, k, n int) {
 	r := xoshiro256.New(uint64(time.Now().UnixNano()))
 
@@ -0,0 +1,233 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/internal/sums"
+)
+
+// A WeightedTree is a collection of weighted items from which items can be
+// sampled with probability proportional to their weight. Unlike an Alias,
+// it supports changing the weights: all operations take O(log n) time
+// for n items.
+//
+// Items are identified by their index, which is assigned by Add and remains
+// valid until the item is removed. Indices of removed items are reused.
+//
+// The zero WeightedTree is empty and ready to use. A WeightedTree is not
+// safe for concurrent use. This includes Sample and SampleN, which modify
+// its internal state.
+type WeightedTree struct {
+	items   []interface{}
+	weights []float64
+	removed []bool
+	free    []int // Removed indices, for reuse.
+
+	// Fenwick tree of partial sums of weights: tree[i] holds the sum of
+	// the weights with indices in [i-lowbit(i+1)+1, i]. Each partial sum
+	// is compensated, and the tree is rebuilt from the weights now and then
+	// so that rounding errors do not accumulate.
+	tree    []sums.Neumaier
+	updates int // Updates since the last rebuild.
+}
+
+// Add adds x to t with weight w and returns its index.
+//
+// Add panics if w is negative, infinite or NaN.
+func (t *WeightedTree) Add(x interface{}, w float64) (i int) {
+	checkWeight(w)
+
+	if n := len(t.free); n > 0 {
+		i = t.free[n-1]
+		t.free = t.free[:n-1]
+		t.items[i] = x
+		t.removed[i] = false
+		t.set(i, w)
+		return i
+	}
+
+	i = len(t.items)
+	t.items = append(t.items, x)
+	t.weights = append(t.weights, w)
+	t.removed = append(t.removed, false)
+
+	// The new node covers i itself and the nodes i-1, i-2, i-4, ...
+	// up to but excluding i-lowbit(i+1).
+	var node sums.Neumaier
+	node.Add(w)
+	for step := 1; step < lowbit(i+1); step *= 2 {
+		node.Add(t.tree[i-step].Value())
+	}
+	t.tree = append(t.tree, node)
+	return i
+}
+
+// Item returns the item with index i.
+func (t *WeightedTree) Item(i int) interface{} {
+	t.check(i)
+	return t.items[i]
+}
+
+// Len returns the number of items in t.
+func (t *WeightedTree) Len() int { return len(t.items) - len(t.free) }
+
+// Remove removes the item with index i from t.
+func (t *WeightedTree) Remove(i int) {
+	t.check(i)
+	t.set(i, 0)
+	t.items[i] = nil
+	t.removed[i] = true
+	t.free = append(t.free, i)
+}
+
+// Sample returns the index of a random item, chosen with probability
+// proportional to its weight. It returns -1 if the total weight is zero.
+//
+// Sample may rebuild the tree of partial sums to get rid of rounding errors,
+// so it must not be called concurrently with any other method, including
+// Sample itself, without synchronization. r must not be nil.
+func (t *WeightedTree) Sample(r rand.Source64) int {
+	for {
+		total := t.Total()
+		if total <= 0 {
+			return -1
+		}
+
+		i := t.search(randstat.Float64(r) * total)
+		if i < len(t.weights) && t.weights[i] > 0 {
+			return i
+		}
+
+		// Rounding errors caused us to land beyond the last item
+		// or on a zero-weight item. Get rid of them and try again.
+		t.rebuild()
+	}
+}
+
+// SampleN appends to buf the indices of a random sample, without
+// replacement, of k items and returns the resulting slice.
+//
+// The items are drawn one at a time, each time with probability
+// proportional to weight among the items not yet drawn. If fewer than k items
+// have non-zero weight, all of these are appended. r must not be nil.
+//
+// The time complexity is O(k log n).
+func (t *WeightedTree) SampleN(r rand.Source64, k int, buf []int) []int {
+	// Drawn items are excluded by negating their weights.
+	start := len(buf)
+	for ; k > 0; k-- {
+		i := t.Sample(r)
+		if i < 0 {
+			break
+		}
+		buf = append(buf, i)
+		t.weights[i] = -t.weights[i]
+		t.update(i, t.weights[i])
+	}
+
+	for _, i := range buf[start:] {
+		t.weights[i] = -t.weights[i]
+		t.update(i, t.weights[i])
+	}
+	return buf
+}
+
+// Set sets the weight of the item with index i to w.
+//
+// Set panics if w is negative, infinite or NaN.
+func (t *WeightedTree) Set(i int, w float64) {
+	t.check(i)
+	checkWeight(w)
+	t.set(i, w)
+}
+
+// Total returns the sum of the weights of all items in t.
+func (t *WeightedTree) Total() float64 {
+	var sum sums.Neumaier
+	for i := len(t.tree); i > 0; i -= lowbit(i) {
+		sum.Add(t.tree[i-1].Value())
+	}
+	return math.Max(0, sum.Value())
+}
+
+// Weight returns the weight of the item with index i.
+func (t *WeightedTree) Weight(i int) float64 {
+	t.check(i)
+	return t.weights[i]
+}
+
+func (t *WeightedTree) check(i int) {
+	if i < 0 || i >= len(t.items) || t.removed[i] {
+		panic("sampling: WeightedTree index out of range")
+	}
+}
+
+func checkWeight(w float64) {
+	if !(w >= 0 && w <= math.MaxFloat64) {
+		panic("sampling: weight must be finite and non-negative")
+	}
+}
+
+func (t *WeightedTree) set(i int, w float64) {
+	delta := w - t.weights[i]
+	t.weights[i] = w
+	t.update(i, delta)
+}
+
+// update adds delta to the partial sums that include index i.
+func (t *WeightedTree) update(i int, delta float64) {
+	t.updates++
+	if t.updates > 2*len(t.tree)+64 {
+		t.rebuild()
+		return
+	}
+
+	for j := i + 1; j <= len(t.tree); j += lowbit(j) {
+		t.tree[j-1].Add(delta)
+	}
+}
+
+// rebuild recomputes the tree from the weights in O(n) time.
+func (t *WeightedTree) rebuild() {
+	t.updates = 0
+	for i, w := range t.weights {
+		t.tree[i].Set(math.Max(0, w)) // Negative while excluded by SampleN.
+	}
+	for j := 1; j <= len(t.tree); j++ {
+		if p := j + lowbit(j); p <= len(t.tree) {
+			t.tree[p-1].Add(t.tree[j-1].Value())
+		}
+	}
+}
+
+// search returns the smallest index i such that the sum of the weights
+// up to and including i exceeds u, or len(t.tree) if there is none.
+func (t *WeightedTree) search(u float64) int {
+	pos := 0
+	for step := 1 << (bits.Len(uint(len(t.tree))) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next <= len(t.tree) {
+			if s := t.tree[next-1].Value(); s <= u {
+				pos = next
+				u -= s
+			}
+		}
+	}
+	return pos
+}
+
+func lowbit(i int) int { return i & -i }
@@ -0,0 +1,209 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/sampling"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedTree(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tree    sampling.WeightedTree
+		r       = xoshiro256.New(0x7733)
+		weights = make(map[int]float64) // Model of tree.
+		keys    []int                   // Keys of weights, in insertion order.
+	)
+
+	assert.Equal(t, -1, tree.Sample(r))
+	assert.Empty(t, tree.SampleN(r, 3, nil))
+
+	for op := 0; op < 20000; op++ {
+		switch x := randstat.Intn(r, 10); {
+		case x < 4 || len(weights) == 0:
+			w := float64(randstat.Intn(r, 100))
+			i := tree.Add(op, w)
+			_, exists := weights[i]
+			require.False(t, exists)
+			weights[i] = w
+			keys = append(keys, i)
+
+		case x < 8:
+			i := keys[randstat.Intn(r, len(keys))]
+			w := randstat.Float64(r) * 1e3
+			tree.Set(i, w)
+			weights[i] = w
+
+		default:
+			j := randstat.Intn(r, len(keys))
+			i := keys[j]
+			keys[j] = keys[len(keys)-1]
+			keys = keys[:len(keys)-1]
+			tree.Remove(i)
+			delete(weights, i)
+			assert.Panics(t, func() { tree.Weight(i) })
+		}
+
+		if op%1000 == 0 {
+			var total float64
+			for _, i := range keys {
+				require.Equal(t, weights[i], tree.Weight(i))
+				total += weights[i]
+			}
+			require.Equal(t, len(weights), tree.Len())
+			require.InEpsilon(t, total, tree.Total(), 1e-12)
+
+			if total > 0 {
+				i := tree.Sample(r)
+				require.Greater(t, weights[i], 0.)
+			}
+		}
+	}
+}
+
+func TestWeightedTreeSample(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tree    sampling.WeightedTree
+		r       = xoshiro256.New(0x5a3)
+		weights = []float64{3, 0, 1, 7, 7, 0, 2, 10, .5, 4, 1, 1, 1}
+	)
+
+	for i, w := range weights {
+		tree.Add(i, w+1)
+	}
+	tree.Remove(len(weights) - 1)
+	for i, w := range weights[:len(weights)-1] {
+		tree.Set(i, w)
+	}
+	weights = weights[:len(weights)-1]
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	x := make([]int64, 100000)
+	for i := range x {
+		x[i] = int64(tree.Sample(r))
+	}
+	stattest.Discrete(t, x, func(k int64) float64 {
+		if k >= int64(len(weights)) {
+			return 0
+		}
+		return weights[k] / total
+	})
+}
+
+func TestWeightedTreeSampleN(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tree    sampling.WeightedTree
+		r       = xoshiro256.New(0x5a3)
+		weights = []float64{5, 0, 1, 2, 0, 1000}
+	)
+	for i, w := range weights {
+		tree.Add(i, w)
+	}
+
+	// Four items with non-zero weight, so we get at most four.
+	first := make([]int, len(weights))
+	for round := 0; round < 10000; round++ {
+		sample := tree.SampleN(r, 5, nil)
+		require.Len(t, sample, 4)
+
+		seen := make(map[int]bool)
+		for _, i := range sample {
+			require.False(t, seen[i])
+			require.NotZero(t, weights[i])
+			seen[i] = true
+		}
+		first[sample[0]]++
+	}
+
+	// Weights are restored after sampling.
+	for i, w := range weights {
+		assert.Equal(t, w, tree.Weight(i))
+	}
+	assert.Equal(t, 1008., tree.Total())
+
+	// The first draw is an ordinary weighted draw.
+	assert.InDelta(t, 1000./1008, float64(first[5])/10000, .005)
+
+	sample := tree.SampleN(r, 2, []int{-1})
+	assert.Len(t, sample, 3)
+	assert.Equal(t, -1, sample[0])
+}
+
+func TestWeightedTreeDrift(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tree sampling.WeightedTree
+		r    = xoshiro256.New(0xd71f7)
+	)
+	for i := 0; i < 100; i++ {
+		tree.Add(nil, 1e-3)
+	}
+
+	// Large weights come and go, while the small ones remain.
+	for i := 0; i < 100000; i++ {
+		j := randstat.Intn(r, 100)
+		tree.Set(j, 1e15*randstat.Float64(r))
+		tree.Set(j, 1e-3)
+	}
+	assert.InEpsilon(t, .1, tree.Total(), 1e-9)
+}
+
+func TestWeightedTreePanics(t *testing.T) {
+	t.Parallel()
+
+	var tree sampling.WeightedTree
+	i := tree.Add("x", 1)
+
+	assert.Panics(t, func() { tree.Add("y", -1) })
+	assert.Panics(t, func() { tree.Set(i, math.NaN()) })
+	assert.Panics(t, func() { tree.Set(i, math.Inf(1)) })
+	assert.Panics(t, func() { tree.Set(i+1, 1) })
+	assert.Panics(t, func() { tree.Item(-1) })
+
+	tree.Remove(i)
+	assert.Panics(t, func() { tree.Remove(i) })
+	assert.Panics(t, func() { tree.Item(i) })
+}
+
+func BenchmarkWeightedTree(b *testing.B) {
+	var tree sampling.WeightedTree
+	for _, w := range benchmarkWeights() {
+		tree.Add(nil, w)
+	}
+	r := xoshiro256.New(1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j := tree.Sample(r)
+		tree.Set(j, tree.Weight(j)/2+1)
+	}
+}
@@ -20,7 +20,6 @@
 package sampling
 
 import (
-	"math"
 	"math/rand"
 
 	"github.com/greatroar/randstat"
@@ -37,14 +36,12 @@ func ints31_int(samplesize int, n int32, r rand.Source64, buf []int) []int {
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
@@ -66,14 +63,12 @@ func ints63_int(samplesize int, n int64, r rand.Source64, buf []int) []int {
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
@@ -95,14 +90,12 @@ func ints31_int32(samplesize int, n int32, r rand.Source, buf []int32) []int32 {
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
@@ -124,14 +117,12 @@ func ints31_int64(samplesize int, n int32, r rand.Source, buf []int64) []int64 {
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
@@ -153,14 +144,12 @@ func ints63_int64(samplesize int, n int64, r rand.Source64, buf []int64) []int64
 	sample := buf[len(buf)-samplesize:]
 
 	var (
-		w = float64(1)
+		l = newAlgorithmL(samplesize)
 		i = float64(samplesize)
-		k = 1 / float64(samplesize)
 		N = float64(n)
 	)
 	for {
-		w *= math.Exp(math.Log(random01(r)) * k)
-		i += 1 + math.Floor(math.Log(random01(r))/math.Log1p(-w))
+		i += 1 + l.skip(r)
 		if i >= N {
 			break
 		}
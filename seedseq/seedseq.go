@@ -0,0 +1,265 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package seedseq derives initial states for random number generators
+// from user-supplied seeds, in the manner of NumPy's SeedSequence.
+//
+// A SeedSequence hashes an arbitrary amount of entropy into a pool,
+// from which it generates states of any size. It can spawn child
+// SeedSequences that produce independent states, to seed the generators
+// of parallel tasks in a reproducible way.
+//
+// The algorithm is the one of NumPy, designed by Melissa O'Neill and
+// Robert Kern, with a pool size of four 32-bit words. A SeedSequence
+// constructed from the same entropy produces the same states as NumPy's.
+package seedseq
+
+import (
+	"encoding/binary"
+
+	"github.com/greatroar/randstat/chacha"
+	"github.com/greatroar/randstat/pcg"
+	"github.com/greatroar/randstat/philox"
+	"github.com/greatroar/randstat/xoroshiro128"
+	"github.com/greatroar/randstat/xoshiro256"
+)
+
+const (
+	poolSize = 4
+
+	initA    = 0x43b0d7e5
+	multA    = 0x931e8875
+	initB    = 0x8b51f9dd
+	multB    = 0x58f38ded
+	mixMultL = 0xca01f9dd
+	mixMultR = 0x4973f715
+	xshift   = 16
+)
+
+// A SeedSequence generates initial states for random number generators.
+//
+// It is not safe for concurrent use by multiple goroutines.
+type SeedSequence struct {
+	entropy  []uint32
+	spawnKey []uint32
+	pool     [poolSize]uint32
+	nspawned uint64
+}
+
+// New returns a SeedSequence for the given entropy.
+//
+// The entropy is converted to 32-bit words in the same way as NumPy
+// converts a sequence of non-negative integers: zero is a single zero word,
+// other values are split into the least number of words needed,
+// least significant first. New(x, y) thus corresponds to
+// numpy.random.SeedSequence([x, y]).
+func New(entropy ...uint64) *SeedSequence {
+	var words []uint32
+	for _, x := range entropy {
+		words = appendWords(words, x)
+	}
+	return newSeq(words, nil)
+}
+
+// FromBytes returns a SeedSequence for entropy given as a byte string.
+//
+// The bytes are packed into little-endian 32-bit words,
+// the last of which is padded with zeros if needed.
+func FromBytes(entropy []byte) *SeedSequence {
+	words := make([]uint32, (len(entropy)+3)/4)
+	for i := range words {
+		var w [4]byte
+		copy(w[:], entropy[4*i:])
+		words[i] = binary.LittleEndian.Uint32(w[:])
+	}
+	return newSeq(words, nil)
+}
+
+func newSeq(entropy, spawnKey []uint32) *SeedSequence {
+	s := &SeedSequence{entropy: entropy, spawnKey: spawnKey}
+
+	// Pad the entropy to the pool size so that it cannot collide
+	// with a spawn key.
+	assembled := append([]uint32(nil), entropy...)
+	if len(spawnKey) > 0 {
+		for len(assembled) < poolSize {
+			assembled = append(assembled, 0)
+		}
+	}
+	assembled = append(assembled, spawnKey...)
+
+	s.mixEntropy(assembled)
+	return s
+}
+
+func appendWords(dst []uint32, x uint64) []uint32 {
+	if x == 0 {
+		return append(dst, 0)
+	}
+	for ; x > 0; x >>= 32 {
+		dst = append(dst, uint32(x))
+	}
+	return dst
+}
+
+func hashmix(value uint32, hashConst *uint32) uint32 {
+	value ^= *hashConst
+	*hashConst *= multA
+	value *= *hashConst
+	value ^= value >> xshift
+	return value
+}
+
+func mix(x, y uint32) uint32 {
+	r := mixMultL*x - mixMultR*y
+	r ^= r >> xshift
+	return r
+}
+
+func (s *SeedSequence) mixEntropy(entropy []uint32) {
+	pool := &s.pool
+	hashConst := uint32(initA)
+
+	for i := range pool {
+		var x uint32
+		if i < len(entropy) {
+			x = entropy[i]
+		}
+		pool[i] = hashmix(x, &hashConst)
+	}
+
+	// Mix all bits together so late bits can affect earlier bits.
+	for src := range pool {
+		for dst := range pool {
+			if src != dst {
+				pool[dst] = mix(pool[dst], hashmix(pool[src], &hashConst))
+			}
+		}
+	}
+
+	// Mix in any remaining entropy.
+	for src := len(pool); src < len(entropy); src++ {
+		for dst := range pool {
+			pool[dst] = mix(pool[dst], hashmix(entropy[src], &hashConst))
+		}
+	}
+}
+
+// Spawn returns n child SeedSequences.
+//
+// The children have the same entropy as s, plus a spawn key that consists
+// of the spawn key of s and the child's index among all children ever
+// spawned from s. Calling Spawn(1) twice gives the same children
+// as calling Spawn(2) once.
+func (s *SeedSequence) Spawn(n int) []*SeedSequence {
+	children := make([]*SeedSequence, n)
+	for i := range children {
+		key := append([]uint32(nil), s.spawnKey...)
+		key = appendWords(key, s.nspawned)
+		s.nspawned++
+		children[i] = newSeq(s.entropy, key)
+	}
+	return children
+}
+
+// State32 fills dst with generated state, as 32-bit words.
+// It corresponds to NumPy's generate_state(len(dst), np.uint32).
+//
+// State32 does not change s, so repeated calls produce the same state.
+func (s *SeedSequence) State32(dst []uint32) {
+	hashConst := uint32(initB)
+	for i := range dst {
+		x := s.pool[i%poolSize]
+		x ^= hashConst
+		hashConst *= multB
+		x *= hashConst
+		x ^= x >> xshift
+		dst[i] = x
+	}
+}
+
+// State64 fills dst with generated state, as 64-bit words.
+// It corresponds to NumPy's generate_state(len(dst), np.uint64).
+//
+// State64 does not change s, so repeated calls produce the same state.
+func (s *SeedSequence) State64(dst []uint64) {
+	words := make([]uint32, 2*len(dst))
+	s.State32(words)
+	for i := range dst {
+		dst[i] = uint64(words[2*i]) | uint64(words[2*i+1])<<32
+	}
+}
+
+// ChaCha returns a chacha.Source with the given number of rounds,
+// keyed with 256 bits of state generated by s.
+func (s *SeedSequence) ChaCha(rounds int) *chacha.Source {
+	var words [8]uint32
+	s.State32(words[:])
+
+	var key [32]byte
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(key[4*i:], w)
+	}
+	return chacha.New(key, rounds)
+}
+
+// PCG64 returns a pcg.Source seeded with 256 bits of state generated by s.
+// It produces the same stream as NumPy's PCG64 seeded with the same
+// SeedSequence.
+func (s *SeedSequence) PCG64() *pcg.Source {
+	var st [4]uint64
+	s.State64(st[:])
+	return pcg.NewStream([2]uint64{st[0], st[1]}, [2]uint64{st[2], st[3]})
+}
+
+// PCG64DXSM returns a pcg.DXSM seeded with 256 bits of state generated by s.
+// It produces the same stream as NumPy's PCG64DXSM seeded with the same
+// SeedSequence.
+func (s *SeedSequence) PCG64DXSM() *pcg.DXSM {
+	var st [4]uint64
+	s.State64(st[:])
+	return pcg.NewDXSMStream([2]uint64{st[0], st[1]}, [2]uint64{st[2], st[3]})
+}
+
+// Philox returns a philox.Source with a 128-bit key generated by s.
+func (s *SeedSequence) Philox() *philox.Source {
+	var key [2]uint64
+	s.State64(key[:])
+	return philox.New(key)
+}
+
+// Xoroshiro128 returns a xoroshiro128.Source with its 128-bit state
+// generated by s.
+func (s *SeedSequence) Xoroshiro128() *xoroshiro128.Source {
+	r := &xoroshiro128.Source{}
+	s.State64(r.S[:])
+	if r.S == [2]uint64{} {
+		// Astronomically unlikely, but the all-zero state is invalid.
+		r.S[0] = 1
+	}
+	return r
+}
+
+// Xoshiro256 returns a xoshiro256.Source with its 256-bit state
+// generated by s.
+//
+// Unlike xoshiro256.Source.Seed, which can only reach 2^64 states,
+// this can produce every valid state.
+func (s *SeedSequence) Xoshiro256() *xoshiro256.Source {
+	r := &xoshiro256.Source{}
+	s.State64(r.S[:])
+	if r.S == [4]uint64{} {
+		// Astronomically unlikely, but the all-zero state is invalid.
+		r.S[0] = 1
+	}
+	return r
+}
@@ -0,0 +1,130 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seedseq_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/seedseq"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReference(t *testing.T) {
+	t.Parallel()
+
+	// Reference data from NumPy's test suite, which was generated by
+	// the C++ prototype of SeedSequence.
+	for _, c := range []struct {
+		entropy []uint64
+		state   [4]uint32
+	}{
+		{
+			[]uint64{3735928559, 195939070, 229505742, 305419896},
+			[4]uint32{3914649087, 576849849, 3593928901, 2229911004},
+		},
+		{
+			[]uint64{3668361503, 4165561550, 1661411377, 3634257570},
+			[4]uint32{2240804226, 3691353228, 1365957195, 2654016646},
+		},
+	} {
+		s := seedseq.New(c.entropy...)
+
+		var state [4]uint32
+		s.State32(state[:])
+		assert.Equal(t, c.state, state)
+
+		var state64 [2]uint64
+		s.State64(state64[:])
+		assert.Equal(t, uint64(c.state[1])<<32|uint64(c.state[0]), state64[0])
+		assert.Equal(t, uint64(c.state[3])<<32|uint64(c.state[2]), state64[1])
+
+		// The same entropy as bytes.
+		var b []byte
+		for _, x := range c.entropy {
+			b = append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24))
+		}
+		seedseq.FromBytes(b).State32(state[:])
+		assert.Equal(t, c.state, state)
+	}
+}
+
+func TestEntropyWords(t *testing.T) {
+	t.Parallel()
+
+	state := func(s *seedseq.SeedSequence) (st [8]uint32) {
+		s.State32(st[:])
+		return st
+	}
+
+	// Large values are split into 32-bit words.
+	assert.Equal(t,
+		state(seedseq.New(1, 2, 3)), state(seedseq.New(2<<32|1, 3)))
+	// Padding of the last byte-string word.
+	assert.Equal(t,
+		state(seedseq.New(0x030201)), state(seedseq.FromBytes([]byte{1, 2, 3})))
+}
+
+func TestSpawn(t *testing.T) {
+	t.Parallel()
+
+	state := func(s *seedseq.SeedSequence) (st [4]uint64) {
+		s.State64(st[:])
+		return st
+	}
+
+	a, b := seedseq.New(42), seedseq.New(42)
+	assert.Equal(t, state(a), state(b))
+
+	children := a.Spawn(3)
+	children = append(children, a.Spawn(2)...)
+	more := b.Spawn(5)
+	for i := range children {
+		assert.Equal(t, state(children[i]), state(more[i]))
+	}
+
+	grandchildren := children[0].Spawn(2)
+	seen := make(map[[4]uint64]bool)
+	for _, s := range append(append(children, grandchildren...), a) {
+		st := state(s)
+		assert.False(t, seen[st])
+		seen[st] = true
+	}
+
+	// A spawn key cannot collide with entropy.
+	assert.NotEqual(t, state(seedseq.New(42, 0)), state(more[0]))
+}
+
+func TestGenerators(t *testing.T) {
+	t.Parallel()
+
+	for _, newSource := range []func(*seedseq.SeedSequence) rand.Source64{
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.ChaCha(8) },
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.PCG64() },
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.PCG64DXSM() },
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.Philox() },
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.Xoroshiro128() },
+		func(s *seedseq.SeedSequence) rand.Source64 { return s.Xoshiro256() },
+	} {
+		a := newSource(seedseq.New(1))
+		b := newSource(seedseq.New(1))
+		c := newSource(seedseq.New(1).Spawn(1)[0])
+
+		for i := 0; i < 10; i++ {
+			x := a.Uint64()
+			assert.Equal(t, x, b.Uint64())
+			assert.NotEqual(t, x, c.Uint64())
+		}
+	}
+}
@@ -21,13 +21,40 @@
 // See <http://creativecommons.org/publicdomain/zero/1.0/>.
 
 // Package splitmix64 implements the SplitMix64 random number generator.
+//
+// SplitMix64 is a counter-based generator: its state is advanced by adding
+// a fixed odd constant, the gamma, and its output is a hash of the state.
+// This makes it possible to skip ahead in constant time and to split off
+// independent generators, as described by Steele, Lea and Flood, Fast
+// Splittable Pseudorandom Number Generators, https://doi.org/10.1145/2714064.2660195.
 package splitmix64
 
-import "sync/atomic"
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// Gamma of a Source, 2^64 divided by the golden ratio, rounded to odd.
+const golden = 0x9e3779b97f4a7c15
 
 // A Source is a SplitMix64 random number generator.
 type Source uint64
 
+// At returns the output at position i of a Source seeded with seed,
+// i.e., the value that Uint64 returns after Seed(seed) and Skip(i).
+func At(seed, i uint64) uint64 { return mix64(seed + (i+1)*golden) }
+
+// FillUint64 fills dst with pseudo-random 64-bit values.
+// It is equivalent to, but faster than, calling Uint64 len(dst) times.
+func (s *Source) FillUint64(dst []uint64) {
+	x := uint64(*s)
+	for i := range dst {
+		x += golden
+		dst[i] = mix64(x)
+	}
+	*s = Source(x)
+}
+
 // Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
 func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
 
@@ -35,15 +62,85 @@ func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
 // deterministic state.
 func (s *Source) Seed(seed int64) { *s = Source(seed) }
 
+// Skip advances s by n positions.
+func (s *Source) Skip(n uint64) { *s += Source(n * golden) }
+
+// Split returns a new generator, seeded with the next output of s.
+// Its gamma is derived by a separate mixing function from the state
+// that s advances to after that output, which consumes a position of s.
+//
+// The streams of s and the returned generator are statistically independent.
+// Split can be called on the returned generator, as well as on s,
+// to create a tree of generators, e.g., for fork-join parallelism.
+func (s *Source) Split() *Splittable {
+	parent := Splittable{uint64(*s), golden}
+	child := parent.Split()
+	*s = Source(parent.seed)
+	return child
+}
+
 // Uint64 returns a pseudo-random 64-bit value as a uint64.
 func (s *Source) Uint64() uint64 {
-	*s += 0x9e3779b97f4a7c15
-	z := uint64(*s)
+	*s += golden
+	return mix64(uint64(*s))
+}
+
+func mix64(z uint64) uint64 {
 	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
 	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
 	return z ^ (z >> 31)
 }
 
+// A Splittable is a SplitMix64 random number generator with a configurable
+// gamma. Splittables are created by Split.
+//
+// A Splittable with the same seed and gamma as a Source produces the same
+// stream of random numbers. Splittables produced by the same sequence of
+// Split calls produce the same streams.
+type Splittable struct {
+	seed, gamma uint64
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Splittable) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state. It resets the gamma to that of a Source.
+func (s *Splittable) Seed(seed int64) { *s = Splittable{uint64(seed), golden} }
+
+// Skip advances s by n positions.
+func (s *Splittable) Skip(n uint64) { s.seed += n * s.gamma }
+
+// Split returns a new generator, seeded with the next output of s.
+// Its gamma is derived by a separate mixing function from the state
+// that s advances to after that output, which consumes a position of s.
+//
+// See Source.Split.
+func (s *Splittable) Split() *Splittable {
+	seed := s.Uint64()
+	s.seed += s.gamma
+	return &Splittable{seed: seed, gamma: mixGamma(s.seed)}
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Splittable) Uint64() uint64 {
+	s.seed += s.gamma
+	return mix64(s.seed)
+}
+
+// mixGamma derives an odd gamma with sufficiently many bit transitions
+// from z.
+func mixGamma(z uint64) uint64 {
+	z = (z ^ (z >> 33)) * 0xff51afd7ed558ccd
+	z = (z ^ (z >> 33)) * 0xc4ceb9fe1a85ec53
+	z = (z ^ (z >> 33)) | 1
+
+	if bits.OnesCount64(z^(z>>1)) < 24 {
+		z ^= 0xaaaaaaaaaaaaaaaa
+	}
+	return z
+}
+
 // An AtomicSource is a concurrency-safe SplitMix64 random number generator.
 //
 // All methods on an AtomicSource may safely be called concurrently by
@@ -61,8 +158,5 @@ func (s *AtomicSource) Seed(seed int64) {
 // Uint64Atomic returns a 64-bit random number.
 // It may be safely called by multiple goroutines concurrently.
 func (s *AtomicSource) Uint64() uint64 {
-	z := atomic.AddUint64((*uint64)(s), 0x9e3779b97f4a7c15)
-	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
-	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
-	return z ^ (z >> 31)
+	return mix64(atomic.AddUint64((*uint64)(s), golden))
 }
@@ -24,7 +24,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var _ rand.Source64 = (*splitmix64.Source)(nil)
+var (
+	_ rand.Source64 = (*splitmix64.Source)(nil)
+	_ rand.Source64 = (*splitmix64.Splittable)(nil)
+)
 
 // These are the first 99 outputs from Java's SplittableRandom.nextInt method
 // with a seed of zero.
@@ -90,6 +93,61 @@ func TestSource(t *testing.T) {
 	}
 }
 
+func TestAt(t *testing.T) {
+	for i, want := range jdkOutput0 {
+		assert.Equal(t, want, int64(splitmix64.At(0, uint64(i))))
+	}
+	for i, want := range commonsOutput {
+		assert.Equal(t, want, splitmix64.At(0x1a2b3c4d5e6f7531, uint64(i)))
+	}
+}
+
+func TestSkip(t *testing.T) {
+	for _, n := range []uint64{0, 1, 17, 98} {
+		var s splitmix64.Source
+		s.Skip(n)
+		assert.Equal(t, jdkOutput0[n], int64(s.Uint64()))
+
+		var sp splitmix64.Splittable
+		sp.Seed(0)
+		sp.Skip(n)
+		assert.Equal(t, jdkOutput0[n], int64(sp.Uint64()))
+	}
+
+	// Skipping around the cycle.
+	s := splitmix64.Source(0x1a2b3c4d5e6f7531)
+	s.Skip(1<<63 + 2)
+	s.Skip(1<<63 - 2)
+	assert.Equal(t, commonsOutput[0], s.Uint64())
+}
+
+func TestSplit(t *testing.T) {
+	var (
+		s  = splitmix64.Source(0)
+		sp splitmix64.Splittable
+	)
+	sp.Seed(0)
+
+	// A Splittable seeded like a Source behaves like it.
+	a, b := s.Split(), sp.Split()
+	assert.Equal(t, a, b)
+	// Split consumed two values.
+	assert.Equal(t, jdkOutput0[2], int64(s.Uint64()))
+	assert.Equal(t, jdkOutput0[2], int64(sp.Uint64()))
+
+	// Children and grandchildren produce different streams.
+	seen := make(map[uint64]bool)
+	children := []*splitmix64.Splittable{a, a.Split(), a.Split()}
+	children = append(children, children[1].Split(), children[1].Split())
+	for _, c := range children {
+		for i := 0; i < 100; i++ {
+			x := c.Uint64()
+			assert.False(t, seen[x])
+			seen[x] = true
+		}
+	}
+}
+
 func TestAtomicSource(t *testing.T) {
 	want := make(map[int64]int)
 	for _, x := range jdkOutput0 {
@@ -0,0 +1,43 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoroshiro128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/greatroar/randstat/internal/gf2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The jump polynomials from the C version must be powers of x.
+func TestJumpPoly(t *testing.T) {
+	t.Parallel()
+
+	pow2 := func(k uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), k) }
+
+	for _, c := range []struct {
+		charpoly       gf2.Charpoly
+		jump64, jump96 []uint64
+	}{
+		{charpoly, jump64[:], jump96[:]},
+		{charpolyPP, jump64PP[:], jump96PP[:]},
+	} {
+		assert.Equal(t, c.jump64, c.charpoly.XPow(pow2(64)))
+		assert.Equal(t, c.jump96, c.charpoly.XPow(pow2(96)))
+
+		// x^period = 1.
+		assert.Equal(t, []uint64{1, 0}, c.charpoly.XPow(period))
+	}
+}
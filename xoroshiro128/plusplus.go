@@ -0,0 +1,115 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Derived from http://prng.di.unimi.it/xoroshiro128plusplus.c:
+//
+// Written in 2019 by David Blackman and Sebastiano Vigna (vigna@acm.org)
+//
+// To the extent possible under law, the author has dedicated all copyright
+// and related and neighboring rights to this software to the public domain
+// worldwide. This software is distributed without any warranty.
+//
+// See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+package xoroshiro128
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/greatroar/randstat/internal/gf2"
+)
+
+// A PlusPlus is a xoroshiro128++ 1.0 random number generator.
+//
+// The zero PlusPlus is not usable; it produces a stream of zeros.
+type PlusPlus struct {
+	S [2]uint64 // Generator state (128 bits).
+}
+
+// NewPlusPlus returns a PlusPlus initialized with the given seed.
+// It is equivalent to allocating a PlusPlus and calling Seed on it.
+func NewPlusPlus(seed uint64) *PlusPlus {
+	s := &PlusPlus{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// Advance advances the PlusPlus by n positions.
+func (s *PlusPlus) Advance(n uint64) { advance(&s.S, n, charpolyPP, stepPP) }
+
+// AdvanceBig advances the PlusPlus by n positions, modulo the period 2^128-1.
+// A negative n moves the PlusPlus backwards.
+func (s *PlusPlus) AdvanceBig(n *big.Int) { advanceBig(&s.S, n, charpolyPP, stepPP) }
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *PlusPlus) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Jump advances the PlusPlus by 2^64 positions.
+//
+// It can be used to generate 2^64 non-overlapping subsequences
+// for parallel computations.
+func (s *PlusPlus) Jump() { jump(&s.S, jump64PP[:], stepPP) }
+
+// LongJump advances the PlusPlus by 2^96 positions.
+//
+// It can be used to generate 2^32 starting points, from each of which
+// Jump will generate 2^32 non-overlapping subsequences.
+func (s *PlusPlus) LongJump() { jump(&s.S, jump96PP[:], stepPP) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state.
+// The seed value may be any 64-bit integer.
+//
+// It uses a SplitMix64 generator to turn seed into two pseudo-random numbers,
+// not both zero.
+func (s *PlusPlus) Seed(seed int64) { seedState(&s.S, seed) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *PlusPlus) Uint64() uint64 {
+	r := bits.RotateLeft64(s.S[0]+s.S[1], 17) + s.S[0]
+	stepPP(&s.S)
+	return r
+}
+
+func stepPP(st *[2]uint64) {
+	s0, s1 := st[0], st[1]
+	s1 ^= s0
+	st[0] = bits.RotateLeft64(s0, 49) ^ s1 ^ (s1 << 21)
+	st[1] = bits.RotateLeft64(s1, 28)
+}
+
+// Characteristic polynomial of the state transition.
+var charpolyPP = gf2.Charpoly{0x8dae70779760b081, 0x0031bcf2f855d6e5}
+
+// Jump polynomials from the C version: x^(2^64) and x^(2^96).
+var (
+	jump64PP = [...]uint64{0x2bd7a6a6e99c2ddc, 0x0992ccaf6a6fca05}
+	jump96PP = [...]uint64{0x360fd5f2cf8d5d99, 0x9c6e6877736c46e3}
+)
+
+const headerPP = "xoroshiro128++\x00\x00"
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format starts with a 16-byte header, followed by the two 64-bit
+// integers of state in little-endian format.
+//
+// The returned error is always nil.
+func (s *PlusPlus) MarshalBinary() (data []byte, err error) {
+	return marshal(headerPP, &s.S), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *PlusPlus) UnmarshalBinary(data []byte) error {
+	return unmarshal(headerPP, &s.S, data)
+}
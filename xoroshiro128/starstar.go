@@ -0,0 +1,120 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Derived from http://prng.di.unimi.it/xoroshiro128starstar.c:
+//
+// Written in 2018 by David Blackman and Sebastiano Vigna (vigna@acm.org)
+//
+// To the extent possible under law, the author has dedicated all copyright
+// and related and neighboring rights to this software to the public domain
+// worldwide. This software is distributed without any warranty.
+//
+// See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+// Package xoroshiro128 implements the xoroshiro128** and xoroshiro128++
+// random number generators.
+//
+// These have half the state size of the generators in package xoshiro256,
+// at the cost of a shorter period of 2^128-1.
+package xoroshiro128
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/greatroar/randstat/internal/gf2"
+)
+
+// A Source is a xoroshiro128** 1.0 random number generator.
+//
+// The zero Source is not usable; it produces a stream of zeros.
+type Source struct {
+	S [2]uint64 // Generator state (128 bits).
+}
+
+// New returns a Source initialized with the given seed.
+// It is equivalent to allocating a Source and calling Seed on it.
+func New(seed uint64) *Source {
+	s := &Source{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// Advance advances the Source by n positions.
+func (s *Source) Advance(n uint64) { advance(&s.S, n, charpoly, step) }
+
+// AdvanceBig advances the Source by n positions, modulo the period 2^128-1.
+// A negative n moves the Source backwards.
+func (s *Source) AdvanceBig(n *big.Int) { advanceBig(&s.S, n, charpoly, step) }
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Jump advances the Source by 2^64 positions.
+//
+// It can be used to generate 2^64 non-overlapping subsequences
+// for parallel computations.
+func (s *Source) Jump() { jump(&s.S, jump64[:], step) }
+
+// LongJump advances the Source by 2^96 positions.
+//
+// It can be used to generate 2^32 starting points, from each of which
+// Jump will generate 2^32 non-overlapping subsequences.
+func (s *Source) LongJump() { jump(&s.S, jump96[:], step) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state.
+// The seed value may be any 64-bit integer.
+//
+// It uses a SplitMix64 generator to turn seed into two pseudo-random numbers,
+// not both zero.
+func (s *Source) Seed(seed int64) { seedState(&s.S, seed) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Source) Uint64() uint64 {
+	r := bits.RotateLeft64(s.S[0]*5, 7) * 9
+	step(&s.S)
+	return r
+}
+
+func step(st *[2]uint64) {
+	s0, s1 := st[0], st[1]
+	s1 ^= s0
+	st[0] = bits.RotateLeft64(s0, 24) ^ s1 ^ (s1 << 16)
+	st[1] = bits.RotateLeft64(s1, 37)
+}
+
+// Characteristic polynomial of the state transition.
+var charpoly = gf2.Charpoly{0x095b8f76579aa001, 0x0008828e513b43d5}
+
+// Jump polynomials from the C version: x^(2^64) and x^(2^96).
+var (
+	jump64 = [...]uint64{0xdf900294d8f554a5, 0x170865df4b3201fc}
+	jump96 = [...]uint64{0xd2a98b26625eee7b, 0xdddf9b1090aa7ac1}
+)
+
+const header = "xoroshiro128**\x00\x00"
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format starts with a 16-byte header, followed by the two 64-bit
+// integers of state in little-endian format.
+//
+// The returned error is always nil.
+func (s *Source) MarshalBinary() (data []byte, err error) {
+	return marshal(header, &s.S), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *Source) UnmarshalBinary(data []byte) error {
+	return unmarshal(header, &s.S, data)
+}
@@ -0,0 +1,101 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoroshiro128
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/greatroar/randstat/internal/gf2"
+	"github.com/greatroar/randstat/splitmix64"
+)
+
+// period is the period of the xoroshiro128 generators, 2^128-1.
+var period = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+func advance(st *[2]uint64, n uint64, c gf2.Charpoly, step func(*[2]uint64)) {
+	if n < 2*64 {
+		// Cheaper than the jump, which takes 128 steps.
+		for ; n > 0; n-- {
+			step(st)
+		}
+		return
+	}
+	advanceBig(st, new(big.Int).SetUint64(n), c, step)
+}
+
+func advanceBig(st *[2]uint64, n *big.Int, c gf2.Charpoly, step func(*[2]uint64)) {
+	jump(st, c.XPow(new(big.Int).Mod(n, period)), step)
+}
+
+// jump sets st to p(T) applied to st, where T is the state transition
+// implemented by step.
+func jump(st *[2]uint64, p []uint64, step func(*[2]uint64)) {
+	var s0, s1 uint64
+
+	for i := 0; i < len(p); i++ {
+		for b := byte(0); b < 64; b++ {
+			if p[i]&(1<<b) != 0 {
+				s0 ^= st[0]
+				s1 ^= st[1]
+			}
+			step(st)
+		}
+	}
+
+	*st = [2]uint64{s0, s1}
+}
+
+// seedState uses a SplitMix64 generator to turn seed into two
+// pseudo-random numbers, not both zero.
+func seedState(st *[2]uint64, seed int64) {
+	sm := splitmix64.Source(seed)
+
+retry:
+	s0 := sm.Uint64()
+	s1 := sm.Uint64()
+
+	if s0|s1 == 0 {
+		goto retry
+	}
+
+	*st = [2]uint64{s0, s1}
+}
+
+const marshalSize = 16 + 2*8
+
+func marshal(header string, st *[2]uint64) []byte {
+	data := make([]byte, marshalSize)
+	copy(data, header)
+	for i, x := range st {
+		binary.LittleEndian.PutUint64(data[len(header)+8*i:], x)
+	}
+	return data
+}
+
+func unmarshal(header string, st *[2]uint64, data []byte) error {
+	switch {
+	case len(data) != marshalSize:
+		return errors.New("unmarshal xoroshiro128: incorrect data length")
+	case string(data[:len(header)]) != header:
+		return errors.New("unmarshal xoroshiro128: incorrect header")
+	}
+
+	data = data[len(header):]
+	for i := range st {
+		st[i] = binary.LittleEndian.Uint64(data)
+		data = data[8:]
+	}
+	return nil
+}
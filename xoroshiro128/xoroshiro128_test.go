@@ -0,0 +1,199 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoroshiro128_test
+
+import (
+	"encoding"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/xoroshiro128"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*xoroshiro128.Source)(nil)
+	_ encoding.BinaryUnmarshaler = (*xoroshiro128.Source)(nil)
+	_ rand.Source64              = (*xoroshiro128.Source)(nil)
+
+	_ encoding.BinaryMarshaler   = (*xoroshiro128.PlusPlus)(nil)
+	_ encoding.BinaryUnmarshaler = (*xoroshiro128.PlusPlus)(nil)
+	_ rand.Source64              = (*xoroshiro128.PlusPlus)(nil)
+)
+
+// Common interface of the generators, for testing.
+type source interface {
+	rand.Source64
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	Advance(uint64)
+	AdvanceBig(*big.Int)
+	Jump()
+	LongJump()
+}
+
+type testVector struct {
+	seed uint64
+	r    [15]uint64
+}
+
+type jumpVector struct {
+	seed uint64
+	r    [3]uint64 // First Uint64 after Seed + 1, 2 or 3 Jumps.
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	testSource(t, func() source { return new(xoroshiro128.Source) }, []testVector{
+		// Values generated by the C version, bootstrapped by SplitMix64.
+		{seed: 0x00000000,
+			r: [...]uint64{
+				0xdec90d521e93e35d, 0x6d33ac6f18895e08, 0xab21904eec6fa48a,
+				0x87afdbc188423fbe, 0x64c1fc972d0be37f, 0x747f0f97a0cd98a5,
+				0x489b61f9632ef474, 0x271460c1092811f0, 0xff0e6c13c9f16f25,
+				0xb56a31da37149983, 0x723cf04fbd1ed4ff, 0x56005057f9b3c167,
+				0x651fdcd95e939ac4, 0xcef2e967455e8ec5, 0xa5d2339bc18f65c2,
+			}},
+
+		{seed: 0x123456789,
+			r: [...]uint64{
+				0xff9842a711b532df, 0xd256b85c3b97c612, 0x5225048b90ed95ca,
+				0xa9ce21dea35bebd5, 0xce1b3a7955d5a5cf, 0x9c6342c734f47cf1,
+				0xbe4636d706f89c1c, 0x7338e539782b4f62, 0x896ffa954689a12c,
+				0x846c5c9358eab508, 0xe4660d57fa4d8979, 0xa9224f866ad61cbd,
+				0x570806e507c11b18, 0xbcf473799b20b0e0, 0x274b6caaba3f2452,
+			}},
+	}, []jumpVector{
+		{seed: 0x00000028,
+			r: [...]uint64{
+				0x0d4a9d927798631a, 0x15af6b31fab04aea, 0xd6ae0e699b9e7ccb}},
+
+		{seed: 0x00abac0d,
+			r: [...]uint64{
+				0x194a012306fce006, 0x5b5f3249d7e4f0ee, 0x7c68045acfea2d15}},
+	})
+}
+
+func TestPlusPlus(t *testing.T) {
+	t.Parallel()
+
+	testSource(t, func() source { return new(xoroshiro128.PlusPlus) }, []testVector{
+		// Values generated by the C version, bootstrapped by SplitMix64.
+		{seed: 0x00000001,
+			r: [...]uint64{
+				0x08260b0f1b52fcac, 0x5d9320f71ce29ff1, 0x28197699ec67f190,
+				0x593b393b9d1e5795, 0x38d7e95386fef5e4, 0xdf662f251c40e205,
+				0xd6c55ccd44694a1a, 0xb36f44a48a8d32da, 0x9162923b9ba4a4cc,
+				0xeb8a207f313db88c, 0x871f76acc7039e38, 0xb8445bd0a718149c,
+				0xdc98f1a922ba5d79, 0x20b81caa1b00696f, 0x9889081731c7f6d0,
+			}},
+
+		{seed: 0x05f5e0ff,
+			r: [...]uint64{
+				0x6afc4e6c0a724ee5, 0x4769cc6b147eef9f, 0x4990e90d0fb74d75,
+				0xc75476c3ffcc2208, 0x67763119d4429f5f, 0x34ed87d2c9308133,
+				0x5ac594cf27f85ba2, 0x18c2c38dcbe5c8e8, 0x21a027b0a101a8c8,
+				0x45f1eb2b1130f273, 0x6afa3806fc6ffa3c, 0x80480f6f417894b2,
+				0xc70a2df81aa7db60, 0xd85b19be89ff4742, 0x0df22dbba5312740,
+			}},
+	}, []jumpVector{
+		{seed: 0x00abac0d,
+			r: [...]uint64{
+				0x9e77c1d899cf3e03, 0x04e6fae3c3546fae, 0x2df77d44809b0276}},
+
+		{seed: 0x182321e30c3,
+			r: [...]uint64{
+				0xd97c5b86fe949d41, 0x98176e8d9bf7c3ee, 0x802f276c510161b8}},
+	})
+}
+
+func testSource(t *testing.T, newSource func() source, values []testVector, jumps []jumpVector) {
+	t.Helper()
+
+	s := newSource()
+	for _, c := range values {
+		s.Seed(int64(c.seed))
+		for i, v := range c.r {
+			assert.Equal(t, v, s.Uint64(), "seed = 0x%08x, value %d", c.seed, i)
+		}
+	}
+
+	for _, c := range jumps {
+		for njumps, x := range c.r {
+			s.Seed(int64(c.seed))
+			for i := 0; i <= njumps; i++ {
+				s.Jump()
+			}
+			assert.Equal(t, x, s.Uint64())
+		}
+	}
+
+	pow2 := func(k uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), k) }
+
+	for _, n := range []uint64{0, 1, 127, 128, 1000} {
+		a, b := newSource(), newSource()
+		a.Seed(int64(n))
+		b.Seed(int64(n))
+		for i := uint64(0); i < n; i++ {
+			a.Uint64()
+		}
+		b.Advance(n)
+		assert.Equal(t, a.Uint64(), b.Uint64(), "n = %d", n)
+
+		b.AdvanceBig(new(big.Int).SetInt64(-1 - int64(n)))
+		a.Seed(int64(n))
+		assert.Equal(t, a.Uint64(), b.Uint64(), "n = %d", n)
+	}
+
+	a, b := newSource(), newSource()
+	a.Seed(0xfeed)
+	b.Seed(0xfeed)
+	a.Jump()
+	b.AdvanceBig(pow2(64))
+	assert.Equal(t, a.Uint64(), b.Uint64())
+
+	a.LongJump()
+	b.AdvanceBig(pow2(96))
+	assert.Equal(t, a.Uint64(), b.Uint64())
+
+	// Marshal roundtrip.
+	marshaled, err := a.MarshalBinary()
+	assert.Nil(t, err)
+	expect := a.Uint64()
+	assert.Nil(t, b.UnmarshalBinary(marshaled))
+	assert.Equal(t, expect, b.Uint64())
+
+	assert.NotNil(t, b.UnmarshalBinary(nil))
+	assert.NotNil(t, b.UnmarshalBinary(make([]byte, 4*8)))
+}
+
+func BenchmarkSourceUint64(b *testing.B) {
+	r := xoroshiro128.New(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkPlusPlusUint64(b *testing.B) {
+	r := xoroshiro128.NewPlusPlus(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
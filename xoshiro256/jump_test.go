@@ -0,0 +1,33 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoshiro256
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The jump polynomials from the C version must be powers of x.
+func TestJumpPoly(t *testing.T) {
+	t.Parallel()
+
+	pow2 := func(k uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), k) }
+
+	assert.Equal(t, jump128[:], charpoly.XPow(pow2(128)))
+	assert.Equal(t, jump192[:], charpoly.XPow(pow2(192)))
+
+	// x^period = 1.
+	assert.Equal(t, []uint64{1, 0, 0, 0}, charpoly.XPow(period))
+}
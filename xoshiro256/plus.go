@@ -0,0 +1,88 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Derived from http://prng.di.unimi.it/xoshiro256plus.c:
+//
+// Written in 2018 by David Blackman and Sebastiano Vigna (vigna@acm.org)
+//
+// To the extent possible under law, the author has dedicated all copyright
+// and related and neighboring rights to this software to the public domain
+// worldwide. This software is distributed without any warranty.
+//
+// See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+package xoshiro256
+
+import "math/big"
+
+// A Plus is a xoshiro256+ 1.0 random number generator.
+//
+// Its lowest three bits have low linear complexity. It is a good choice
+// for generating floating-point numbers, which only use the upper 53 bits.
+// For other purposes, use Source or PlusPlus.
+//
+// The zero Plus is not usable; it produces a stream of zeros.
+type Plus struct {
+	S [4]uint64 // Generator state (256 bits).
+}
+
+// NewPlus returns a Plus initialized with the given seed.
+// It is equivalent to allocating a Plus and calling Seed on it.
+func NewPlus(seed uint64) *Plus {
+	s := &Plus{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// Advance advances the Plus by n positions.
+func (s *Plus) Advance(n uint64) { advance(&s.S, n) }
+
+// AdvanceBig advances the Plus by n positions, modulo the period 2^256-1.
+// A negative n moves the Plus backwards.
+func (s *Plus) AdvanceBig(n *big.Int) { advanceBig(&s.S, n) }
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Plus) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Jump advances the Plus by 2^128 positions.
+func (s *Plus) Jump() { jump(&s.S, jump128[:]) }
+
+// LongJump advances the Plus by 2^192 positions.
+func (s *Plus) LongJump() { jump(&s.S, jump192[:]) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state. It does so in the same way as Source.Seed.
+func (s *Plus) Seed(seed int64) { seedState(&s.S, seed) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Plus) Uint64() uint64 {
+	r := s.S[0] + s.S[3]
+	step(&s.S)
+	return r
+}
+
+const headerPlus = "xoshiro256+1.0\x00\x00"
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format is the same as that of Source.MarshalBinary,
+// but with a different header.
+//
+// The returned error is always nil.
+func (s *Plus) MarshalBinary() (data []byte, err error) {
+	return marshal(headerPlus, &s.S), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *Plus) UnmarshalBinary(data []byte) error {
+	return unmarshal(headerPlus, &s.S, data)
+}
@@ -0,0 +1,87 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Derived from http://prng.di.unimi.it/xoshiro256plusplus.c:
+//
+// Written in 2019 by David Blackman and Sebastiano Vigna (vigna@acm.org)
+//
+// To the extent possible under law, the author has dedicated all copyright
+// and related and neighboring rights to this software to the public domain
+// worldwide. This software is distributed without any warranty.
+//
+// See <http://creativecommons.org/publicdomain/zero/1.0/>.
+
+package xoshiro256
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// A PlusPlus is a xoshiro256++ 1.0 random number generator.
+//
+// The zero PlusPlus is not usable; it produces a stream of zeros.
+type PlusPlus struct {
+	S [4]uint64 // Generator state (256 bits).
+}
+
+// NewPlusPlus returns a PlusPlus initialized with the given seed.
+// It is equivalent to allocating a PlusPlus and calling Seed on it.
+func NewPlusPlus(seed uint64) *PlusPlus {
+	s := &PlusPlus{}
+	s.Seed(int64(seed))
+	return s
+}
+
+// Advance advances the PlusPlus by n positions.
+func (s *PlusPlus) Advance(n uint64) { advance(&s.S, n) }
+
+// AdvanceBig advances the PlusPlus by n positions, modulo the period 2^256-1.
+// A negative n moves the PlusPlus backwards.
+func (s *PlusPlus) AdvanceBig(n *big.Int) { advanceBig(&s.S, n) }
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *PlusPlus) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Jump advances the PlusPlus by 2^128 positions.
+func (s *PlusPlus) Jump() { jump(&s.S, jump128[:]) }
+
+// LongJump advances the PlusPlus by 2^192 positions.
+func (s *PlusPlus) LongJump() { jump(&s.S, jump192[:]) }
+
+// Seed uses the provided seed value to initialize the generator to a
+// deterministic state. It does so in the same way as Source.Seed.
+func (s *PlusPlus) Seed(seed int64) { seedState(&s.S, seed) }
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *PlusPlus) Uint64() uint64 {
+	r := bits.RotateLeft64(s.S[0]+s.S[3], 23) + s.S[0]
+	step(&s.S)
+	return r
+}
+
+const headerPlusPlus = "xoshiro256++1.0\x00"
+
+// MarshalBinary encodes s in a binary format for serialization.
+//
+// The format is the same as that of Source.MarshalBinary,
+// but with a different header.
+//
+// The returned error is always nil.
+func (s *PlusPlus) MarshalBinary() (data []byte, err error) {
+	return marshal(headerPlusPlus, &s.S), nil
+}
+
+// UnmarshalBinary decodes s from the binary format used by MarshalBinary.
+func (s *PlusPlus) UnmarshalBinary(data []byte) error {
+	return unmarshal(headerPlusPlus, &s.S, data)
+}
@@ -0,0 +1,139 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoshiro256
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// A Sharded is a concurrency-safe xoshiro256** random number generator.
+//
+// All methods on a Sharded may safely be called concurrently by multiple
+// goroutines. Its state is divided into shards, each of which is a Source
+// protected by a spinlock. Shards are not bound to a P or goroutine:
+// each call hashes the address of the caller's stack to pick a starting
+// shard, then locks the first unlocked shard from there. Goroutines running
+// in parallel thus usually start at different shards, and when they
+// collide, one of them moves on to the next shard instead of waiting.
+//
+// Shards are not per-P slots because Go offers no supported way to find
+// the P a goroutine runs on. A goroutine's stack address is stable enough
+// to serve as an affinity hint, and with four shards per P, collisions that
+// the probing has to resolve are rare.
+//
+// A Sharded must be created by NewSharded; the zero Sharded has no shards
+// and panics when used.
+//
+// Shard i starts at the state of a Source seeded with the same seed,
+// advanced by i Jumps. The shards therefore produce non-overlapping
+// subsequences of that Source's stream, so no value is ever produced twice
+// from the same position, but which shard serves a call depends on
+// scheduling. The sequence of values produced by a Sharded is therefore
+// not reproducible, not even when it is used by a single goroutine.
+// Use a Source per goroutine when reproducibility is required.
+type Sharded struct {
+	shards []shard
+	mask   uintptr
+}
+
+type shard struct {
+	locked uint32
+	src    Source
+
+	// Keep shards on separate cache lines, even when the slice is not
+	// aligned to a cache line boundary.
+	_ [128 - 8 - unsafe.Sizeof(Source{})]byte
+}
+
+// NewSharded returns a Sharded initialized with the given seed.
+//
+// The number of shards is four times runtime.GOMAXPROCS(0),
+// rounded up to a power of two.
+func NewSharded(seed uint64) *Sharded {
+	n := 1
+	for n < 4*runtime.GOMAXPROCS(0) {
+		n *= 2
+	}
+
+	s := &Sharded{
+		shards: make([]shard, n),
+		mask:   uintptr(n - 1),
+	}
+	s.Seed(int64(seed))
+	return s
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+func (s *Sharded) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// Seed uses the provided seed value to reinitialize the generator to
+// a deterministic state, as described in the documentation for Sharded.
+//
+// Calls to Uint64 that happen concurrently with Seed may use the old state
+// or the new one.
+func (s *Sharded) Seed(seed int64) {
+	var src Source
+	src.Seed(seed)
+
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.lock()
+		sh.src = src
+		sh.unlock()
+		src.Jump()
+	}
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (s *Sharded) Uint64() uint64 {
+	if len(s.shards) == 0 {
+		panic("xoshiro256: Sharded not created by NewSharded")
+	}
+	sh := s.lockAny(hint())
+	x := sh.src.Uint64()
+	sh.unlock()
+	return x
+}
+
+// lockAny locks the first available shard, starting the search at index i.
+func (s *Sharded) lockAny(i uintptr) *shard {
+	for {
+		for j := uintptr(0); j <= s.mask; j++ {
+			sh := &s.shards[(i+j)&s.mask]
+			if sh.tryLock() {
+				return sh
+			}
+		}
+		runtime.Gosched()
+	}
+}
+
+func (sh *shard) lock() {
+	for !sh.tryLock() {
+		runtime.Gosched()
+	}
+}
+
+func (sh *shard) tryLock() bool { return atomic.CompareAndSwapUint32(&sh.locked, 0, 1) }
+func (sh *shard) unlock()       { atomic.StoreUint32(&sh.locked, 0) }
+
+// hint returns an index that is likely to be different for goroutines
+// running in parallel. It hashes the address of a stack variable,
+// since every goroutine has its own stack.
+func hint() uintptr {
+	var x byte
+	h := uint64(uintptr(unsafe.Pointer(&x)) >> 10)
+	return uintptr((h * 0x9e3779b97f4a7c15) >> 32)
+}
@@ -0,0 +1,126 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoshiro256_test
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/greatroar/randstat/splitmix64"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ rand.Source64 = (*xoshiro256.Sharded)(nil)
+
+func TestSharded(t *testing.T) {
+	t.Parallel()
+
+	const (
+		ngoroutines  = 8
+		perGoroutine = 10000
+		seed         = 0x5eed
+	)
+
+	// Number of shards, as chosen by NewSharded.
+	nshards := 1
+	for nshards < 4*runtime.GOMAXPROCS(0) {
+		nshards *= 2
+	}
+
+	s := xoshiro256.NewSharded(seed)
+
+	var (
+		mu    sync.Mutex
+		count = make(map[uint64]int)
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < ngoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			values := make([]uint64, perGoroutine)
+			for j := range values {
+				values[j] = s.Uint64()
+			}
+
+			mu.Lock()
+			for _, x := range values {
+				count[x]++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Each shard must have produced a prefix of its stream, which is that
+	// of a Source with the same seed, advanced by a number of Jumps.
+	// Every value must come from such a prefix, and no position in it
+	// may have been used twice.
+	ref := xoshiro256.New(seed)
+	for i := 0; i < nshards; i++ {
+		src := *ref
+		for {
+			x := src.Uint64()
+			if count[x] == 0 {
+				break
+			}
+			require.Equal(t, 1, count[x], "shard %d produced 0x%x twice", i, x)
+			delete(count, x)
+		}
+		ref.Jump()
+	}
+	assert.Empty(t, count, "values not from any shard's stream")
+
+	// Single-goroutine use takes values from the same streams.
+	s.Seed(seed)
+	x := s.Uint64()
+
+	ref = xoshiro256.New(seed)
+	found := false
+	for i := 0; i < nshards && !found; i++ {
+		start := *ref
+		found = start.Uint64() == x
+		ref.Jump()
+	}
+	assert.True(t, found)
+
+	assert.Panics(t, func() { new(xoshiro256.Sharded).Uint64() })
+}
+
+func benchmarkParallel(b *testing.B, r rand.Source64) {
+	b.SetBytes(8)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Uint64()
+		}
+	})
+}
+
+func BenchmarkShardedParallel(b *testing.B) {
+	benchmarkParallel(b, xoshiro256.NewSharded(uint64(time.Now().UnixNano())))
+}
+
+func BenchmarkAtomicSourceParallel(b *testing.B) {
+	var r splitmix64.AtomicSource
+	r.Seed(time.Now().UnixNano())
+	benchmarkParallel(b, &r)
+}
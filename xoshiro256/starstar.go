@@ -20,15 +20,18 @@
 //
 // See <http://creativecommons.org/publicdomain/zero/1.0/>.
 
-// Package xoshiro256 implements the xoshiro256** random number generator.
+// Package xoshiro256 implements the xoshiro256 family of random number
+// generators: xoshiro256**, xoshiro256+ and xoshiro256++.
+//
+// All three have a period of 2^256-1 and support jumping ahead.
+// Source, the ** variant, is a good general-purpose choice.
+// Plus is slightly faster, but its lowest bits have low linear complexity,
+// so it is best used to generate floating-point numbers.
 package xoshiro256
 
 import (
-	"encoding/binary"
-	"errors"
+	"math/big"
 	"math/bits"
-
-	"github.com/greatroar/randstat/splitmix64"
 )
 
 // A Source is a xoshiro256** 1.0 random number generator.
@@ -50,31 +53,46 @@ func New(seed uint64) *Source {
 	return s
 }
 
+// Advance advances the Source by n positions.
+func (s *Source) Advance(n uint64) { advance(&s.S, n) }
+
+// AdvanceBig advances the Source by n positions, modulo the period 2^256-1.
+// A negative n moves the Source backwards.
+func (s *Source) AdvanceBig(n *big.Int) { advanceBig(&s.S, n) }
+
+// FillUint64 fills dst with pseudo-random 64-bit values.
+// It is equivalent to, but faster than, calling Uint64 len(dst) times.
+func (s *Source) FillUint64(dst []uint64) {
+	// This is Uint64 in a loop, with the state kept in registers.
+	s0, s1, s2, s3 := s.S[0], s.S[1], s.S[2], s.S[3]
+	for i := range dst {
+		dst[i] = bits.RotateLeft64(5*s1, 7) * 9
+
+		t := s1 << 17
+		s2 ^= s0
+		s3 ^= s1
+		s1 ^= s2
+		s0 ^= s3
+		s2 ^= t
+		s3 = bits.RotateLeft64(s3, 45)
+	}
+	s.S = [4]uint64{s0, s1, s2, s3}
+}
+
 // Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
 func (s *Source) Int63() int64 { return int64(s.Uint64() >> 1) }
 
 // Jump advances the Source by 2^128 positions.
-func (s *Source) Jump() {
-	jump := [...]uint64{
-		0x180ec6d33cfd0aba, 0xd5a61266f0c9392c,
-		0xa9582618e03fc9aa, 0x39abdc4529b1661c}
-
-	var s0, s1, s2, s3 uint64
-
-	for i := 0; i < len(jump); i++ {
-		for b := byte(0); b < 64; b++ {
-			if jump[i]&(1<<b) != 0 {
-				s0 ^= s.S[0]
-				s1 ^= s.S[1]
-				s2 ^= s.S[2]
-				s3 ^= s.S[3]
-			}
-			s.Uint64()
-		}
-	}
+//
+// It can be used to generate 2^128 non-overlapping subsequences
+// for parallel computations.
+func (s *Source) Jump() { jump(&s.S, jump128[:]) }
 
-	s.S = [4]uint64{s0, s1, s2, s3}
-}
+// LongJump advances the Source by 2^192 positions.
+//
+// It can be used to generate 2^64 starting points, from each of which
+// Jump will generate 2^64 non-overlapping subsequences.
+func (s *Source) LongJump() { jump(&s.S, jump192[:]) }
 
 // Seed uses the provided seed value to initialize the generator to a
 // deterministic state.
@@ -82,43 +100,16 @@ func (s *Source) Jump() {
 //
 // It uses a SplitMix64 generator to turn seed into four non-zero
 // pseudo-random numbers.
-func (s *Source) Seed(seed int64) {
-	sm := splitmix64.Source(seed)
-
-retry:
-	s0 := sm.Uint64()
-	s1 := sm.Uint64()
-	s2 := sm.Uint64()
-	s3 := sm.Uint64()
-
-	if s0|s1|s2|s3 == 0 {
-		goto retry
-	}
-
-	s.S = [4]uint64{s0, s1, s2, s3}
-}
+func (s *Source) Seed(seed int64) { seedState(&s.S, seed) }
 
 // Uint64 returns a pseudo-random 64-bit value as a uint64.
 func (s *Source) Uint64() uint64 {
-	st := &s.S
-	r := bits.RotateLeft64(5*st[1], 7) * 9
-
-	t := st[1] << 17
-	st[2] ^= st[0]
-	st[3] ^= st[1]
-	st[1] ^= st[2]
-	st[0] ^= st[3]
-	st[2] ^= t
-
-	st[3] = bits.RotateLeft64(st[3], 45)
-
+	r := bits.RotateLeft64(5*s.S[1], 7) * 9
+	step(&s.S)
 	return r
 }
 
-const (
-	header      = "xoshiro256**1.0\x00"
-	marshalSize = len(header) + 4*8
-)
+const header = "xoshiro256**1.0\x00"
 
 // MarshalBinary encodes s in a binary format for serialization.
 //
@@ -127,27 +118,10 @@ const (
 //
 // The returned error is always nil.
 func (s *Source) MarshalBinary() (data []byte, err error) {
-	data = make([]byte, marshalSize)
-	copy(data, header)
-	for i, x := range s.S {
-		binary.LittleEndian.PutUint64(data[len(header)+8*i:], x)
-	}
-	return
+	return marshal(header, &s.S), nil
 }
 
 // UnmarshalBinary decodes s from the binary format used by MarshalBinary.
 func (s *Source) UnmarshalBinary(data []byte) error {
-	switch {
-	case len(data) != marshalSize:
-		return errors.New("unmarshal xoshiro256: incorrect data length")
-	case string(data[:len(header)]) != header:
-		return errors.New("unmarshal xoshiro256: incorrect header")
-	}
-
-	data = data[len(header):]
-	for i := range s.S {
-		s.S[i] = binary.LittleEndian.Uint64(data)
-		data = data[8:]
-	}
-	return nil
+	return unmarshal(header, &s.S, data)
 }
@@ -14,6 +14,7 @@ package xoshiro256_test
 
 import (
 	"encoding"
+	"math/big"
 	"math/rand"
 	"testing"
 
@@ -112,6 +113,45 @@ func TestJump(t *testing.T) {
 	}
 }
 
+func TestAdvance(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []uint64{0, 1, 255, 256, 1000, 123456} {
+		a, b := xoshiro256.New(n), xoshiro256.New(n)
+		for i := uint64(0); i < n; i++ {
+			a.Uint64()
+		}
+		b.Advance(n)
+		assert.Equal(t, a.S, b.S, "n = %d", n)
+
+		// Back to the start.
+		b.AdvanceBig(new(big.Int).SetInt64(-int64(n)))
+		assert.Equal(t, xoshiro256.New(n).S, b.S, "n = %d", n)
+	}
+
+	pow2 := func(k uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), k) }
+
+	a, b := xoshiro256.New(0x3b3b), xoshiro256.New(0x3b3b)
+	a.Jump()
+	b.AdvanceBig(pow2(128))
+	assert.Equal(t, a.S, b.S)
+
+	a.LongJump()
+	b.AdvanceBig(pow2(192))
+	assert.Equal(t, a.S, b.S)
+
+	// Advance in pieces.
+	a.Advance(1<<64 - 1)
+	a.Advance(1)
+	b.AdvanceBig(pow2(64))
+	assert.Equal(t, a.S, b.S)
+
+	// Moving forward by the period is a no-op.
+	period := new(big.Int).Sub(pow2(256), big.NewInt(1))
+	b.AdvanceBig(period)
+	assert.Equal(t, a.S, b.S)
+}
+
 func TestMarshal(t *testing.T) {
 	t.Parallel()
 
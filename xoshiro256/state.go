@@ -0,0 +1,137 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoshiro256
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/greatroar/randstat/internal/gf2"
+	"github.com/greatroar/randstat/splitmix64"
+)
+
+// The generators in this package share their state transition, the linear
+// engine xoshiro256. They differ only in the scrambler that computes
+// an output from the state.
+
+// step advances st by one position.
+func step(st *[4]uint64) {
+	t := st[1] << 17
+	st[2] ^= st[0]
+	st[3] ^= st[1]
+	st[1] ^= st[2]
+	st[0] ^= st[3]
+	st[2] ^= t
+
+	st[3] = bits.RotateLeft64(st[3], 45)
+}
+
+// Characteristic polynomial of the xoshiro256 state transition.
+var charpoly = gf2.Charpoly{
+	0x9d116f2bb0f0f001, 0x0280002bcefd1a5e,
+	0x04b4edcf26259f85, 0x0003c03c3f3ecb19,
+}
+
+// period is the period of xoshiro256, 2^256-1.
+var period = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Jump polynomials from the C version: x^(2^128) and x^(2^192).
+var (
+	jump128 = [...]uint64{
+		0x180ec6d33cfd0aba, 0xd5a61266f0c9392c,
+		0xa9582618e03fc9aa, 0x39abdc4529b1661c}
+	jump192 = [...]uint64{
+		0x76e15d3efefdcbbf, 0xc5004e441c522fb3,
+		0x77710069854ee241, 0x39109bb02acbe635}
+)
+
+func advance(st *[4]uint64, n uint64) {
+	if n < 4*64 {
+		// Cheaper than the jump, which takes 256 steps.
+		for ; n > 0; n-- {
+			step(st)
+		}
+		return
+	}
+	advanceBig(st, new(big.Int).SetUint64(n))
+}
+
+func advanceBig(st *[4]uint64, n *big.Int) {
+	jump(st, charpoly.XPow(new(big.Int).Mod(n, period)))
+}
+
+// jump sets st to p(T) applied to st, where T is the state transition.
+func jump(st *[4]uint64, p []uint64) {
+	var s0, s1, s2, s3 uint64
+
+	for i := 0; i < len(p); i++ {
+		for b := byte(0); b < 64; b++ {
+			if p[i]&(1<<b) != 0 {
+				s0 ^= st[0]
+				s1 ^= st[1]
+				s2 ^= st[2]
+				s3 ^= st[3]
+			}
+			step(st)
+		}
+	}
+
+	*st = [4]uint64{s0, s1, s2, s3}
+}
+
+// seedState uses a SplitMix64 generator to turn seed into four non-zero
+// pseudo-random numbers.
+func seedState(st *[4]uint64, seed int64) {
+	sm := splitmix64.Source(seed)
+
+retry:
+	s0 := sm.Uint64()
+	s1 := sm.Uint64()
+	s2 := sm.Uint64()
+	s3 := sm.Uint64()
+
+	if s0|s1|s2|s3 == 0 {
+		goto retry
+	}
+
+	*st = [4]uint64{s0, s1, s2, s3}
+}
+
+const marshalSize = 16 + 4*8
+
+func marshal(header string, st *[4]uint64) []byte {
+	data := make([]byte, marshalSize)
+	copy(data, header)
+	for i, x := range st {
+		binary.LittleEndian.PutUint64(data[len(header)+8*i:], x)
+	}
+	return data
+}
+
+func unmarshal(header string, st *[4]uint64, data []byte) error {
+	switch {
+	case len(data) != marshalSize:
+		return errors.New("unmarshal xoshiro256: incorrect data length")
+	case string(data[:len(header)]) != header:
+		return errors.New("unmarshal xoshiro256: incorrect header")
+	}
+
+	data = data[len(header):]
+	for i := range st {
+		st[i] = binary.LittleEndian.Uint64(data)
+		data = data[8:]
+	}
+	return nil
+}
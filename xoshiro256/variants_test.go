@@ -0,0 +1,181 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xoshiro256_test
+
+import (
+	"encoding"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*xoshiro256.Plus)(nil)
+	_ encoding.BinaryUnmarshaler = (*xoshiro256.Plus)(nil)
+	_ rand.Source64              = (*xoshiro256.Plus)(nil)
+
+	_ encoding.BinaryMarshaler   = (*xoshiro256.PlusPlus)(nil)
+	_ encoding.BinaryUnmarshaler = (*xoshiro256.PlusPlus)(nil)
+	_ rand.Source64              = (*xoshiro256.PlusPlus)(nil)
+)
+
+// Common interface of the generators, for testing.
+type source interface {
+	rand.Source64
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	Advance(uint64)
+	AdvanceBig(*big.Int)
+	Jump()
+	LongJump()
+}
+
+func TestPlus(t *testing.T) {
+	t.Parallel()
+
+	testVariant(t, new(xoshiro256.Plus), []testVector{
+		// Values generated by the C version, bootstrapped by SplitMix64.
+		{seed: 0x00000000,
+			r: [...]uint64{
+				0xdaac60e1ed6a4f9b, 0x3156a1da0dc08435, 0xf9ba3e3285d046ab,
+				0x4fd194611dba7b01, 0x40b78599c31791bf, 0x03b1dd310503d6f4,
+				0xb238d3a721d5092b, 0x11017bba8a0f8adf, 0xa6a988bed1f59149,
+				0xdb4000fb8d550622, 0x5b3947becb71ef9d, 0x53cda86134220dba,
+				0x95aa43de2a55bfe9, 0x2a6a597cc890c649, 0xa159be94778c6782,
+			}},
+
+		{seed: 0x123456789,
+			r: [...]uint64{
+				0xed1a6d222c4bc997, 0x75f8bfc3b265041e, 0xa7ac8e2c7607a80f,
+				0x5f0ee4dae40cfec0, 0x9d8af050d0b705d7, 0x834ff01337a76bba,
+				0x3b06c7411257740e, 0xf7fca77ba28033f3, 0x2567874feec612cb,
+				0x5725665e18fb29cb, 0x8efa41e317e9ac0e, 0xc4083af6b0c94783,
+				0xca882b2bda0cd659, 0x058f216a2f305d6a, 0xa14026ce58bca21d,
+			}},
+	}, []jumpVector{
+		{seed: 0x00000028,
+			r: [...]uint64{
+				0x9b35b37b21a07c88, 0xf48e8e306ea6a26b, 0xb7960adb1737a6b2}},
+
+		{seed: 0x182321e30c3,
+			r: [...]uint64{
+				0x40dd2697ee360344, 0xf85f3d6a4f50d9af, 0xc43516ef69b0fd1b}},
+	})
+}
+
+func TestPlusPlus(t *testing.T) {
+	t.Parallel()
+
+	testVariant(t, new(xoshiro256.PlusPlus), []testVector{
+		// Values generated by the C version, bootstrapped by SplitMix64.
+		{seed: 0x00000001,
+			r: [...]uint64{
+				0xcfc5d07f6f03c29b, 0xbf424132963fe08d, 0x19a37d5757aaf520,
+				0xbf08119f05cd56d6, 0x2f47184b86186fa4, 0x97299fcae7202345,
+				0xfca3c79508f41507, 0x85fea5c90363f221, 0x18bae5b30d334bd0,
+				0x226113c9f026ec16, 0xeb9e0ef9dccfe649, 0x57efaedd9f6cffb3,
+				0x128ae2d5697640d6, 0x65033a4eee505049, 0x16e9453ed54a88ba,
+			}},
+
+		{seed: 0x05f5e0ff,
+			r: [...]uint64{
+				0x081e8d4c130ede1b, 0xa056f46c69fc911e, 0xabe59e4b63418012,
+				0xfee9548e0364cfcd, 0x90906d4933431892, 0xe49c587e21542a5d,
+				0x03da4a18f0d0a847, 0xcef60af4ab56f252, 0xd3148a3f5e4f71cd,
+				0x78299922b65788c4, 0x0640a6860e6d3088, 0x7b9c4fa85019fd76,
+				0xc8fbc57e3b37f84b, 0xc06805be3d4ab1ae, 0xdf64988769fa0c13,
+			}},
+	}, []jumpVector{
+		{seed: 0x00abac0d,
+			r: [...]uint64{
+				0x6ab705c93434e2e6, 0xaf4223f71b1f72bb, 0xb9e3ac3186772d50}},
+
+		{seed: 0x182321e30c3,
+			r: [...]uint64{
+				0xe5f43706e6ca0988, 0x002b62abf9e495c4, 0x7fded7dbcfb47559}},
+	})
+}
+
+type testVector struct {
+	seed uint64
+	r    [15]uint64
+}
+
+type jumpVector struct {
+	seed uint64
+	r    [3]uint64 // First Uint64 after Seed + 1, 2 or 3 Jumps.
+}
+
+func testVariant(t *testing.T, s source, values []testVector, jumps []jumpVector) {
+	t.Helper()
+
+	for _, c := range values {
+		s.Seed(int64(c.seed))
+		for i, v := range c.r {
+			assert.Equal(t, v, s.Uint64(), "seed = 0x%08x, value %d", c.seed, i)
+		}
+	}
+
+	for _, c := range jumps {
+		for njumps, x := range c.r {
+			s.Seed(int64(c.seed))
+			for i := 0; i <= njumps; i++ {
+				s.Jump()
+			}
+			assert.Equal(t, x, s.Uint64())
+		}
+	}
+
+	// All variants share the state transition of Source.
+	ref := xoshiro256.New(0xfeed)
+	s.Seed(0xfeed)
+	ref.LongJump()
+	s.LongJump()
+	ref.Advance(1000)
+	s.Advance(1000)
+
+	marshaled, err := s.MarshalBinary()
+	assert.Nil(t, err)
+	refMarshaled, _ := ref.MarshalBinary()
+	assert.Equal(t, refMarshaled[16:], marshaled[16:])
+	assert.NotEqual(t, refMarshaled[:16], marshaled[:16])
+
+	// Marshal roundtrip.
+	expect := s.Uint64()
+	assert.Nil(t, s.UnmarshalBinary(marshaled))
+	assert.Equal(t, expect, s.Uint64())
+
+	assert.NotNil(t, s.UnmarshalBinary(refMarshaled))
+}
+
+func BenchmarkPlusUint64(b *testing.B) {
+	r := xoshiro256.NewPlus(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
+
+func BenchmarkPlusPlusUint64(b *testing.B) {
+	r := xoshiro256.NewPlusPlus(0)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		r.Uint64()
+	}
+}
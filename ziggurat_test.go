@@ -0,0 +1,119 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package randstat_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/greatroar/randstat"
+	"github.com/greatroar/randstat/internal/stattest"
+	"github.com/greatroar/randstat/xoshiro256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormal(t *testing.T) {
+	t.Parallel()
+
+	const (
+		n            = 100000
+		mean, stddev = -3, 2
+	)
+
+	r := xoshiro256.New(0x7a9e)
+	x := make([]float64, n)
+	var sum, sumsq float64
+	var ntail int
+	for i := range x {
+		x[i] = randstat.Normal(r, mean, stddev)
+		sum += x[i]
+		sumsq += x[i] * x[i]
+		if math.Abs(x[i]-mean) > 3.6541528853610088*stddev {
+			ntail++
+		}
+	}
+
+	m := sum / n
+	v := sumsq/n - m*m
+	assert.InDelta(t, mean, m, .02)
+	assert.InEpsilon(t, stddev*stddev, v, .02)
+	// P(tail) = erfc(3.654/√2) ≈ 2.58e-4; allow 4σ.
+	assert.InDelta(t, 2.58e-4, float64(ntail)/n, 4*5.1e-5)
+
+	stattest.KS(t, x, func(x float64) float64 {
+		return .5 * math.Erfc(-(x-mean)/(stddev*math.Sqrt2))
+	})
+}
+
+func TestExponential(t *testing.T) {
+	t.Parallel()
+
+	const (
+		n    = 100000
+		rate = 1.5
+	)
+
+	r := xoshiro256.New(0x4a11)
+	x := make([]float64, n)
+	var sum float64
+	var ntail int
+	for i := range x {
+		x[i] = randstat.Exponential(r, rate)
+		assert.GreaterOrEqual(t, x[i], 0.)
+		sum += x[i]
+		if x[i] > 7.69711747013104972/rate {
+			ntail++
+		}
+	}
+
+	assert.InEpsilon(t, 1/rate, sum/n, .02)
+	// P(tail) = exp(-7.697) ≈ 4.5e-4; allow 4σ.
+	assert.InDelta(t, 4.54e-4, float64(ntail)/n, 4*6.7e-5)
+
+	stattest.KS(t, x, func(x float64) float64 { return -math.Expm1(-rate * x) })
+}
+
+func BenchmarkNormalStd(b *testing.B) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i := 0; i < b.N; i++ {
+		r.NormFloat64()
+	}
+}
+
+func BenchmarkNormalUs(b *testing.B) {
+	r := rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+
+	for i := 0; i < b.N; i++ {
+		randstat.Normal(r, 0, 1)
+	}
+}
+
+func BenchmarkExponentialStd(b *testing.B) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i := 0; i < b.N; i++ {
+		r.ExpFloat64()
+	}
+}
+
+func BenchmarkExponentialUs(b *testing.B) {
+	r := rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+
+	for i := 0; i < b.N; i++ {
+		randstat.Exponential(r, 1)
+	}
+}